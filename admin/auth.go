@@ -0,0 +1,193 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"gopkg.in/resty.v1"
+)
+
+// authProvider adds the credentials to each request, according to the
+// authentication method set in the configuration. The TLS client certificate,
+// when set, is presented by the transport instead, so it can be combined with
+// any of the providers.
+type authProvider interface {
+	authenticate(ctx context.Context, r *resty.Request) error
+
+	// invalidate drops the current credentials, after the broker rejected
+	// them. It returns false if the provider cannot supply different ones.
+	invalidate() bool
+}
+
+// newAuthProvider returns the provider for the authentication method set in
+// the configuration, or nil if the requests only carry the TLS client
+// certificate, if any
+func newAuthProvider(config *Config, tlsConfig *tls.Config) (authProvider, error) {
+	methods := []string{}
+	if config.AuthToken != "" || config.AuthTokenFile != "" {
+		methods = append(methods, "the auth token")
+	}
+	if config.OAuth2 != nil {
+		methods = append(methods, "the OAuth2 authentication")
+	}
+	if config.Exec != nil {
+		methods = append(methods, "the exec credential plugin")
+	}
+	if config.BasicAuthUser != "" {
+		methods = append(methods, "the basic authentication")
+	}
+	if len(methods) > 1 {
+		return nil, fmt.Errorf("only one authentication method can be set, got %s", strings.Join(methods, " and "))
+	}
+
+	if config.BasicAuthUser != "" {
+		return newBasicAuth(config)
+	}
+
+	tokens, err := newTokenSource(config, tlsConfig)
+	if err != nil || tokens == nil {
+		return nil, err
+	}
+	return &tokenAuth{tokens: tokens}, nil
+}
+
+// tokenAuth sends a bearer token, like Pulsar's AuthenticationToken
+type tokenAuth struct {
+	tokens tokenSource
+}
+
+func (a *tokenAuth) authenticate(ctx context.Context, r *resty.Request) error {
+	token, err := a.tokens.token(ctx)
+	if err != nil {
+		return err
+	}
+	r.SetHeader("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a *tokenAuth) invalidate() bool {
+	return a.tokens.invalidate()
+}
+
+// basicAuth sends a user and password, like Pulsar's AuthenticationBasic
+type basicAuth struct {
+	authorization string
+}
+
+func newBasicAuth(config *Config) (*basicAuth, error) {
+	if config.BasicAuthPassword != "" && config.BasicAuthPasswordFile != "" {
+		return nil, errors.New("the basic authentication password and password file cannot be both set")
+	}
+
+	password := config.BasicAuthPassword
+	if config.BasicAuthPasswordFile != "" {
+		data, err := ioutil.ReadFile(config.BasicAuthPasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the basic authentication password: %s", err)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	}
+
+	credentials := base64.StdEncoding.EncodeToString([]byte(config.BasicAuthUser + ":" + password))
+	return &basicAuth{authorization: "Basic " + credentials}, nil
+}
+
+func (a *basicAuth) authenticate(ctx context.Context, r *resty.Request) error {
+	r.SetHeader("Authorization", a.authorization)
+	return nil
+}
+
+func (a *basicAuth) invalidate() bool {
+	return false
+}
+
+// tokenSource supplies the token sent in the Authorization header of each
+// request
+type tokenSource interface {
+	token(ctx context.Context) (string, error)
+
+	// invalidate drops the current token, after the broker rejected it.
+	// It returns false if the source cannot supply a different token.
+	invalidate() bool
+}
+
+// staticToken is a token given in the configuration
+type staticToken string
+
+func (t staticToken) token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+func (t staticToken) invalidate() bool {
+	return false
+}
+
+// newTokenSource returns the source of the tokens for the given
+// configuration, or nil if the requests are not authenticated with a token.
+// The OAuth2 authorization server is reached with the given TLS settings.
+func newTokenSource(config *Config, tlsConfig *tls.Config) (tokenSource, error) {
+	switch {
+	case config.OAuth2 != nil:
+		return newOAuth2TokenSource(config.OAuth2, tlsConfig)
+	case config.Exec != nil:
+		return newExecTokenSource(config.Exec)
+	}
+
+	token, err := readAuthToken(config)
+	if err != nil || token == "" {
+		return nil, err
+	}
+	return staticToken(token), nil
+}
+
+func readAuthToken(config *Config) (string, error) {
+	if config.AuthToken != "" && config.AuthTokenFile != "" {
+		return "", errors.New("the auth token and the auth token file cannot be both set")
+	}
+
+	if config.AuthTokenFile == "" {
+		return strings.TrimSpace(config.AuthToken), nil
+	}
+
+	data, err := ioutil.ReadFile(config.AuthTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read auth token: %s", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("the auth token file %s is empty", config.AuthTokenFile)
+	}
+	return token, nil
+}
+
+// forwardAuthorization carries the credentials over to the redirect target.
+// The Go HTTP client drops the Authorization header when following a redirect
+// to a different host, which is the common case when a broker redirects the
+// request to the broker that owns the resource.
+var forwardAuthorization = resty.RedirectPolicyFunc(func(req *http.Request, via []*http.Request) error {
+	if auth := via[0].Header.Get("Authorization"); auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	return nil
+})
@@ -0,0 +1,171 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// authorizationRecorder returns a handler that records the Authorization
+// header of the last request, and rejects it if it's not the expected one
+func authorizationRecorder(expected string, last *atomic.Value) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		last.Store(authorization)
+		if authorization != expected {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("[]"))
+	}
+}
+
+func TestTokenAuth(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, config := range []*Config{
+		{AuthToken: "file-token"},
+		{AuthToken: " file-token\n"},
+		{AuthTokenFile: tokenFile},
+	} {
+		var last atomic.Value
+		client := newTestClient(t, config, authorizationRecorder("Bearer file-token", &last))
+
+		if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+			t.Errorf("unexpected error: %s, sent Authorization: %v", err, last.Load())
+		}
+	}
+}
+
+func TestTokenAuthRejected(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, &Config{AuthToken: "revoked", MaxRetries: 3},
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+
+	// A static token cannot be refreshed, so the request is not sent again
+	err := client.RestGet(context.Background(), "/admin/v2/clusters", nil)
+	if sent := atomic.LoadInt32(&requests); StatusCode(err) != http.StatusUnauthorized || sent != 1 {
+		t.Errorf("got error %v after %d requests, expected HTTP 401 after 1", err, sent)
+	}
+}
+
+func TestAuthConfigErrors(t *testing.T) {
+	emptyFile := filepath.Join(t.TempDir(), "empty")
+	if err := ioutil.WriteFile(emptyFile, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, config := range []Config{
+		{AuthToken: "token", AuthTokenFile: emptyFile},
+		{AuthTokenFile: emptyFile},
+		{AuthTokenFile: filepath.Join(t.TempDir(), "missing")},
+		{AuthToken: "token", Exec: &ExecConfig{Command: "true"}},
+	} {
+		config.WebServiceUrl = "http://localhost:8080"
+		if _, err := New(&config); err == nil {
+			t.Errorf("New(%+v): expected an error", config)
+		}
+	}
+}
+
+func TestTokenAuthRedirect(t *testing.T) {
+	var last atomic.Value
+	owner := newTestClient(t, nil, authorizationRecorder("Bearer token", &last))
+
+	// The Go HTTP client drops the credentials when following a redirect to
+	// another host, like from 127.0.0.1 to localhost
+	target := strings.Replace(owner.WebServiceUrl(), "127.0.0.1", "localhost", 1)
+	client := newTestClient(t, &Config{AuthToken: "token"}, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target+r.URL.Path, http.StatusTemporaryRedirect)
+	})
+
+	if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+		t.Errorf("unexpected error: %s, sent Authorization: %v", err, last.Load())
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := ioutil.WriteFile(passwordFile, []byte("pass word\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// base64 of "user:pass word"
+	expected := "Basic dXNlcjpwYXNzIHdvcmQ="
+	for _, config := range []*Config{
+		{BasicAuthUser: "user", BasicAuthPassword: "pass word"},
+		{BasicAuthUser: "user", BasicAuthPasswordFile: passwordFile},
+	} {
+		var last atomic.Value
+		client := newTestClient(t, config, authorizationRecorder(expected, &last))
+
+		if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+			t.Errorf("unexpected error: %s, sent Authorization: %v", err, last.Load())
+		}
+	}
+
+	var requests int32
+	client := newTestClient(t, &Config{BasicAuthUser: "user", BasicAuthPassword: "wrong", MaxRetries: 3},
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+	err := client.RestGet(context.Background(), "/admin/v2/clusters", nil)
+	if sent := atomic.LoadInt32(&requests); StatusCode(err) != http.StatusUnauthorized || sent != 1 {
+		t.Errorf("got error %v after %d requests, expected HTTP 401 after 1", err, sent)
+	}
+}
+
+func TestAuthProvider(t *testing.T) {
+	for _, test := range []struct {
+		config   Config
+		expected string
+	}{
+		{Config{}, "<nil>"},
+		{Config{TLSCertFile: "client.pem", TLSKeyFile: "client.key"}, "<nil>"},
+		{Config{AuthToken: "token"}, "*admin.tokenAuth"},
+		{Config{Exec: &ExecConfig{Command: "true"}}, "*admin.tokenAuth"},
+		{Config{BasicAuthUser: "user"}, "*admin.basicAuth"},
+	} {
+		provider, err := newAuthProvider(&test.config, nil)
+		if actual := fmt.Sprintf("%T", provider); err != nil || actual != test.expected {
+			t.Errorf("newAuthProvider(%+v) = %s, %v, expected %s", test.config, actual, err, test.expected)
+		}
+	}
+
+	for _, config := range []Config{
+		{BasicAuthUser: "user", AuthToken: "token"},
+		{BasicAuthUser: "user", Exec: &ExecConfig{Command: "true"}},
+		{BasicAuthUser: "user", BasicAuthPassword: "pass", BasicAuthPasswordFile: "password"},
+		{BasicAuthUser: "user", BasicAuthPasswordFile: filepath.Join(t.TempDir(), "missing")},
+	} {
+		if _, err := newAuthProvider(&config, nil); err == nil {
+			t.Errorf("newAuthProvider(%+v): expected an error", config)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const (
+	brokersBasePath = "/admin/v2/brokers"
+)
+
+// Brokers gives access to the brokers of the cluster
+type Brokers interface {
+	// Version returns the version of Pulsar run by the broker that answers
+	// the request, eg: 2.10.2. The brokers older than this endpoint answer
+	// with a 404 or 405 error.
+	Version(ctx context.Context) (string, error)
+}
+
+type brokers struct {
+	client *Client
+}
+
+func (b *brokers) Version(ctx context.Context) (string, error) {
+	// The version is sent as plain text, not as a JSON string
+	body, err := b.client.RestCall(ctx, http.MethodGet, brokersBasePath+"/version", nil)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(strings.TrimSpace(string(body)), `"`), nil
+}
@@ -0,0 +1,235 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin provides a Go client for the Pulsar admin REST API.
+//
+// A Client is created from a Config and exposes typed accessors for the
+// different kind of resources, eg:
+//
+//	client, err := admin.New(&admin.Config{WebServiceUrl: "http://localhost:8080"})
+//	clusters, err := client.Clusters().List(context.Background())
+package admin
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/resty.v1"
+)
+
+const (
+	DefaultWebServiceUrl = "http://localhost:8080"
+	DefaultMaxRetries    = 3
+	DefaultRetryTimeout  = 30 * time.Second
+	DefaultUserAgent     = "pulsar-ctl"
+)
+
+// Config holds the settings used to create a Client
+type Config struct {
+	// Admin service URL, eg: http://localhost:8080. It can be a comma
+	// separated list of URLs, eg: http://host-1:8080,http://host-2:8080 or
+	// http://host-1:8080,host-2:8080, to fail over to the next URL when a
+	// broker is unreachable or unavailable.
+	WebServiceUrl string
+
+	// How to pick the URL for each request when WebServiceUrl has more than
+	// one: URLSelectionFailover (default) or URLSelectionRoundRobin
+	URLSelection string
+
+	// Path to the file with the trusted TLS certificates, in PEM format.
+	// If empty, the system certificate pool is used.
+	TLSTrustCertsFilePath string
+
+	// Client certificate and private key, in PEM format, presented to the
+	// server when it requests a TLS client certificate, eg: to authenticate
+	// like Pulsar's AuthenticationTls
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// Accept untrusted TLS certificates from the server
+	TLSAllowInsecureConnection bool
+
+	// Verify that the server certificate matches the host name we connect
+	// to, when it's signed by the TLSTrustCertsFilePath certificates.
+	// Disabled by default, like in the Pulsar Java client. The certificates
+	// signed by the system roots are always verified.
+	TLSEnableHostnameVerification bool
+
+	// Token used to authenticate with the brokers. Alternatively, the token
+	// can be read from AuthTokenFile.
+	AuthToken     string
+	AuthTokenFile string
+
+	// Obtain the tokens from an OAuth2 authorization server, with the client
+	// credentials grant, instead of using AuthToken
+	OAuth2 *OAuth2Config
+
+	// Obtain the tokens by running an external command, instead of using
+	// AuthToken
+	Exec *ExecConfig
+
+	// Credentials for the HTTP basic authentication, like Pulsar's
+	// AuthenticationBasic. The password can be read from
+	// BasicAuthPasswordFile instead.
+	BasicAuthUser         string
+	BasicAuthPassword     string
+	BasicAuthPasswordFile string
+
+	// Number of times a request is retried after a connection error or a
+	// 429, 500, 502, 503 or 504 response. POST requests are not idempotent, so they are only
+	// retried if RetryPost is set.
+	MaxRetries int
+	RetryPost  bool
+
+	// Maximum time spent retrying a request. Zero means no limit besides
+	// MaxRetries.
+	RetryTimeout time.Duration
+
+	// When set, the requests that modify the state (PUT, POST and DELETE)
+	// are written to DryRunOutput instead of being sent. GET requests are
+	// still sent, since they don't have side effects.
+	DryRun       bool
+	DryRunOutput io.Writer
+
+	// Level of detail of the HTTP tracing written to TraceOutput: 1 for the
+	// requests, redirects and response status, 2 to add the headers and 3
+	// to add the bodies. Zero disables the tracing.
+	Verbosity   int
+	TraceOutput io.Writer
+
+	// Write an equivalent curl command to TraceOutput for each request
+	PrintCurl bool
+
+	// Maximum time for a single HTTP request, including the redirects.
+	// Zero means no timeout, besides the deadline of the context passed to
+	// each call.
+	RequestTimeout time.Duration
+
+	// User-Agent header sent with the requests, eg: pulsar-ctl 2.1.0. It
+	// defaults to DefaultUserAgent.
+	UserAgent string
+}
+
+// DefaultConfig returns a configuration pointing to a local Pulsar standalone
+func DefaultConfig() *Config {
+	return &Config{
+		WebServiceUrl: DefaultWebServiceUrl,
+		MaxRetries:    DefaultMaxRetries,
+		RetryTimeout:  DefaultRetryTimeout,
+	}
+}
+
+// Client is a client for the Pulsar admin REST API. It is safe for
+// concurrent use.
+type Client struct {
+	serviceUrls  *serviceUrls
+	auth         authProvider
+	maxRetries   int
+	retryPost    bool
+	retryTimeout time.Duration
+	dryRun       bool
+	dryRunOutput io.Writer
+	verbosity    int
+	printCurl    bool
+	traceOutput  io.Writer
+	curlTLSArgs  []string
+	userAgent    string
+	rest         *resty.Client
+}
+
+// New creates a Client from the given configuration
+func New(config *Config) (*Client, error) {
+	serviceUrls, err := parseServiceUrls(config.WebServiceUrl, config.URLSelection)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := newAuthProvider(config, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	dryRunOutput := config.DryRunOutput
+	if dryRunOutput == nil {
+		dryRunOutput = os.Stdout
+	}
+
+	traceOutput := config.TraceOutput
+	if traceOutput == nil {
+		traceOutput = os.Stderr
+	}
+
+	userAgent := config.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
+	c := &Client{
+		serviceUrls:  serviceUrls,
+		auth:         auth,
+		maxRetries:   config.MaxRetries,
+		retryPost:    config.RetryPost,
+		retryTimeout: config.RetryTimeout,
+		dryRun:       config.DryRun,
+		dryRunOutput: dryRunOutput,
+		verbosity:    config.Verbosity,
+		printCurl:    config.PrintCurl,
+		traceOutput:  traceOutput,
+		curlTLSArgs:  curlTLSArgs(config),
+		userAgent:    userAgent,
+	}
+
+	c.rest = resty.New().
+		SetRedirectPolicy(resty.FlexibleRedirectPolicy(20), forwardAuthorization,
+			resty.RedirectPolicyFunc(c.traceRedirect)).
+		SetTLSClientConfig(tlsConfig).
+		SetTimeout(config.RequestTimeout)
+
+	return c, nil
+}
+
+// WebServiceUrl returns the admin service URL the client is currently using
+func (c *Client) WebServiceUrl() string {
+	return c.serviceUrls.current()
+}
+
+func (c *Client) Brokers() Brokers {
+	return &brokers{client: c}
+}
+
+func (c *Client) Clusters() Clusters {
+	return &clusters{client: c}
+}
+
+func (c *Client) FailureDomains() FailureDomains {
+	return &failureDomains{client: c}
+}
+
+func (c *Client) Namespaces() Namespaces {
+	return &namespaces{client: c}
+}
+
+func (c *Client) Tenants() Tenants {
+	return &tenants{client: c}
+}
+
+func (c *Client) Topics() Topics {
+	return &topics{client: c}
+}
@@ -0,0 +1,53 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestClient starts a server with the given handler and returns a client
+// pointing to it. The config can set any option besides the URL.
+func newTestClient(t *testing.T, config *Config, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	if config == nil {
+		config = &Config{}
+	}
+	config.WebServiceUrl = server.URL
+
+	client, err := New(config)
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	return client
+}
+
+// statusSequence returns a handler that answers with the given statuses in
+// turn, and 200 once they are exhausted, counting the requests
+func statusSequence(requests *int32, statuses ...int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(requests, 1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.Write([]byte("[]"))
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import "context"
+
+type ClusterData struct {
+	ServiceUrl          string `json:"serviceUrl"`
+	ServiceUrlTls       string `json:"serviceUrlTls"`
+	BrokerServiceUrl    string `json:"brokerServiceUrl"`
+	BrokerServiceUrlTls string `json:"brokerServiceUrlTls"`
+
+	// For given Cluster1(us-west1, us-east1) and Cluster2(us-west2, us-east2)
+	// Peer: [us-west1 -> us-west2] and [us-east1 -> us-east2]
+	PeerClusterNames []string `json:"peerClusterNames"`
+}
+
+const (
+	clustersBasePath = "/admin/v2/clusters"
+)
+
+func clusterPath(name string) string {
+	return clustersBasePath + "/" + name
+}
+
+// Clusters gives access to the configuration of Pulsar clusters
+type Clusters interface {
+	// List returns the names of all the clusters, including "global"
+	List(ctx context.Context) ([]string, error)
+
+	// Get returns the configuration of a cluster
+	Get(ctx context.Context, name string) (ClusterData, error)
+
+	// Create configures a new cluster
+	Create(ctx context.Context, name string, cluster ClusterData) error
+
+	// Update replaces the configuration of an existing cluster
+	Update(ctx context.Context, name string, cluster ClusterData) error
+
+	// Delete removes an existing cluster
+	Delete(ctx context.Context, name string) error
+}
+
+type clusters struct {
+	client *Client
+}
+
+func (c *clusters) List(ctx context.Context) ([]string, error) {
+	return c.client.RestGetStringList(ctx, clustersBasePath)
+}
+
+func (c *clusters) Get(ctx context.Context, name string) (ClusterData, error) {
+	cluster := ClusterData{}
+	err := c.client.RestGet(ctx, clusterPath(name), &cluster)
+	return cluster, err
+}
+
+func (c *clusters) Create(ctx context.Context, name string, cluster ClusterData) error {
+	return c.client.RestPut(ctx, clusterPath(name), cluster)
+}
+
+func (c *clusters) Update(ctx context.Context, name string, cluster ClusterData) error {
+	return c.client.RestPost(ctx, clusterPath(name), cluster)
+}
+
+func (c *clusters) Delete(ctx context.Context, name string) error {
+	return c.client.RestDelete(ctx, clusterPath(name))
+}
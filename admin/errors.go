@@ -0,0 +1,104 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gopkg.in/resty.v1"
+)
+
+// Error is returned when an admin request fails, either because the broker
+// could not be reached or because it responded with an error status
+type Error struct {
+	// Method and path of the failed request
+	Method string
+	Path   string
+
+	// HTTP status code of the response, or 0 if no response was received
+	StatusCode int
+
+	// Reason given by the broker, or the HTTP status text if the broker did
+	// not give any
+	Reason string
+
+	// Underlying error when no response was received, eg: connection refused
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s %s failed: %s", e.Method, e.Path, e.Err)
+	}
+	return fmt.Sprintf("%s %s failed: %s (HTTP %d)", e.Method, e.Path, e.Reason, e.StatusCode)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// AuthError is returned when the credentials for a request cannot be
+// obtained, eg: the OAuth2 authorization server rejected the client
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("authentication failed: %s", e.Err)
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorReason is the body of the error responses from the brokers
+type ErrorReason struct {
+	Reason string `json:"reason"`
+}
+
+func responseError(method string, path string, response *resty.Response) error {
+	reason := http.StatusText(response.StatusCode())
+
+	// Try to parse response as JSON
+	var errorReason ErrorReason
+	if err := json.Unmarshal(response.Body(), &errorReason); err == nil && errorReason.Reason != "" {
+		reason = errorReason.Reason
+	}
+
+	return &Error{
+		Method:     method,
+		Path:       path,
+		StatusCode: response.StatusCode(),
+		Reason:     reason,
+	}
+}
+
+// StatusCode returns the HTTP status code of an admin API error, also when
+// it's wrapped, or 0 if the error does not come from a broker response
+func StatusCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound tells whether the error is due to a resource that does not exist
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
@@ -0,0 +1,48 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestStatusCode(t *testing.T) {
+	notFound := &Error{Method: http.MethodGet, Path: "/admin/v2/clusters/missing", StatusCode: http.StatusNotFound}
+
+	for _, test := range []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"nil", nil, 0},
+		{"other error", errors.New("failed"), 0},
+		{"unreachable", &Error{Method: http.MethodGet, Path: "/admin/v2/clusters"}, 0},
+		{"response", notFound, http.StatusNotFound},
+		{"wrapped", fmt.Errorf("failed to get the cluster: %w", notFound), http.StatusNotFound},
+		{"wrapped twice", fmt.Errorf("retry: %w", fmt.Errorf("get: %w", notFound)), http.StatusNotFound},
+		{"authentication", &AuthError{Err: &Error{StatusCode: http.StatusUnauthorized}}, http.StatusUnauthorized},
+	} {
+		if actual := StatusCode(test.err); actual != test.expected {
+			t.Errorf("%s: got %d, expected %d", test.name, actual, test.expected)
+		}
+	}
+
+	if !IsNotFound(fmt.Errorf("wrapped: %w", notFound)) {
+		t.Error("a wrapped not found error is not detected")
+	}
+}
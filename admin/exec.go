@@ -0,0 +1,124 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// ExecConfig holds the settings of an exec credential plugin: an external
+// command that prints a token, in the style of kubectl's exec plugins. The
+// command writes on stdout a JSON object like:
+//
+//	{"token": "eyJhbGciOi...", "expiry": "2018-10-01T12:00:00Z"}
+//
+// The token is reused until its expiry, or only for the current invocation
+// if the expiry is missing.
+type ExecConfig struct {
+	Command string
+	Args    []string
+
+	// Directory where the tokens are cached until they expire, so that the
+	// command is not run by every invocation. Empty disables the cache.
+	CacheDir string
+}
+
+// execTokenSource runs the credential command when it needs a new token
+type execTokenSource struct {
+	command string
+	args    []string
+	cache   tokenCache
+
+	lock    sync.Mutex
+	current *cachedToken
+}
+
+func newExecTokenSource(config *ExecConfig) (*execTokenSource, error) {
+	if config.Command == "" {
+		return nil, errors.New("the exec credential plugin command is not set")
+	}
+
+	return &execTokenSource{
+		command: config.Command,
+		args:    config.Args,
+		cache:   newTokenCache(config.CacheDir, append([]string{config.Command}, config.Args...)...),
+	}, nil
+}
+
+func (s *execTokenSource) token(ctx context.Context) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.current != nil && (s.current.Expiry.IsZero() || s.current.valid()) {
+		return s.current.Token, nil
+	}
+
+	if cached := s.cache.read(); cached.valid() {
+		s.current = cached
+		return cached.Token, nil
+	}
+
+	token, err := s.run(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.current = token
+	if !token.Expiry.IsZero() {
+		s.cache.write(token)
+	}
+	return token.Token, nil
+}
+
+func (s *execTokenSource) invalidate() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.current = nil
+	s.cache.remove()
+	return true
+}
+
+// run runs the credential command, which prints the token and its expiry
+// in the same format as they are cached
+func (s *execTokenSource) run(ctx context.Context) (*cachedToken, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.command, s.args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("credential command %s failed: %s: %s", s.command, err, message)
+		}
+		return nil, fmt.Errorf("credential command %s failed: %s", s.command, err)
+	}
+
+	token := &cachedToken{}
+	if err := json.Unmarshal(stdout.Bytes(), token); err != nil {
+		return nil, fmt.Errorf("invalid output of the credential command %s: %s", s.command, err)
+	}
+	if token.Token == "" {
+		return nil, fmt.Errorf("invalid output of the credential command %s: no token", s.command)
+	}
+	return token, nil
+}
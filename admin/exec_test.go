@@ -0,0 +1,146 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingCommand returns a credential command that prints token-1, token-2,
+// etc, in turn, with the given expiry, and a function returning how many
+// times it ran
+func countingCommand(t *testing.T, expiry time.Time) (*ExecConfig, func() int) {
+	counter := filepath.Join(t.TempDir(), "count")
+	expiryJson := ""
+	if !expiry.IsZero() {
+		expiryJson = fmt.Sprintf(`, "expiry": "%s"`, expiry.Format(time.RFC3339))
+	}
+
+	script := fmt.Sprintf(`n=$(( $(cat %s 2>/dev/null || echo 0) + 1 )); echo $n > %s; echo '{"token": "token-'$n'"%s}'`,
+		counter, counter, expiryJson)
+	runs := func() int {
+		data, _ := ioutil.ReadFile(counter)
+		var n int
+		fmt.Sscan(string(data), &n)
+		return n
+	}
+	return &ExecConfig{Command: "sh", Args: []string{"-c", script}}, runs
+}
+
+func TestExecAuth(t *testing.T) {
+	exec, runs := countingCommand(t, time.Time{})
+	var last atomic.Value
+	client := newTestClient(t, &Config{Exec: exec}, authorizationRecorder("Bearer token-1", &last))
+
+	// Without an expiry, the token is reused for the whole invocation
+	for i := 0; i < 3; i++ {
+		if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+			t.Fatalf("unexpected error: %s, sent Authorization: %v", err, last.Load())
+		}
+	}
+	if runs() != 1 {
+		t.Errorf("the command ran %d times, expected 1", runs())
+	}
+}
+
+func TestExecAuthRefresh(t *testing.T) {
+	exec, runs := countingCommand(t, time.Time{})
+	var last atomic.Value
+	client := newTestClient(t, &Config{Exec: exec}, authorizationRecorder("Bearer token-2", &last))
+
+	// The first token is rejected, so the command runs again for a new one
+	if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+		t.Fatalf("unexpected error: %s, sent Authorization: %v", err, last.Load())
+	}
+	if runs() != 2 {
+		t.Errorf("the command ran %d times, expected 2", runs())
+	}
+
+	// But only once per request
+	client = newTestClient(t, &Config{Exec: exec}, authorizationRecorder("Bearer other", &last))
+	if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); StatusCode(err) != http.StatusUnauthorized {
+		t.Errorf("got error %v, expected HTTP 401", err)
+	}
+	if runs() != 4 {
+		t.Errorf("the command ran %d times, expected 4", runs())
+	}
+}
+
+func TestExecAuthCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	exec, runs := countingCommand(t, time.Now().Add(time.Hour))
+	exec.CacheDir = cacheDir
+
+	// The token is cached across clients, like across invocations
+	for i := 0; i < 2; i++ {
+		var last atomic.Value
+		client := newTestClient(t, &Config{Exec: exec}, authorizationRecorder("Bearer token-1", &last))
+		if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+			t.Fatalf("unexpected error: %s, sent Authorization: %v", err, last.Load())
+		}
+	}
+	if runs() != 1 {
+		t.Errorf("the command ran %d times, expected 1", runs())
+	}
+
+	// A token about to expire is not reused
+	expiring, runs := countingCommand(t, time.Now().Add(tokenExpiryMargin/2))
+	expiring.CacheDir = cacheDir
+	for i := 0; i < 2; i++ {
+		var last atomic.Value
+		client := newTestClient(t, &Config{Exec: expiring}, authorizationRecorder(fmt.Sprintf("Bearer token-%d", i+1), &last))
+		if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+			t.Fatalf("unexpected error: %s, sent Authorization: %v", err, last.Load())
+		}
+	}
+	if runs() != 2 {
+		t.Errorf("the command ran %d times, expected 2", runs())
+	}
+}
+
+func TestExecAuthErrors(t *testing.T) {
+	for _, test := range []struct {
+		script   string
+		expected string
+	}{
+		{"echo 'not logged in' >&2; exit 1", "not logged in"},
+		{"echo 'token'", "invalid output"},
+		{`echo '{"expiry": "2018-10-01T12:00:00Z"}'`, "no token"},
+	} {
+		var requests int32
+		client := newTestClient(t, &Config{Exec: &ExecConfig{Command: "sh", Args: []string{"-c", test.script}}, MaxRetries: 3},
+			statusSequence(&requests))
+
+		err := client.RestGet(context.Background(), "/admin/v2/clusters", nil)
+		if _, ok := err.(*AuthError); !ok || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s: got error %v, expected an AuthError with %q", test.script, err, test.expected)
+		}
+		if sent := atomic.LoadInt32(&requests); sent != 0 {
+			t.Errorf("%s: %d requests were sent without credentials", test.script, sent)
+		}
+	}
+
+	if _, err := New(&Config{WebServiceUrl: "http://localhost:8080", Exec: &ExecConfig{}}); err == nil {
+		t.Errorf("expected an error for a missing command")
+	}
+}
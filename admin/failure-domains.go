@@ -0,0 +1,74 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+)
+
+type FailureDomain struct {
+	Brokers []string `json:"brokers"`
+}
+
+func clusterFailureDomainsPath(cluster string, domain string) string {
+	return fmt.Sprintf("%s/%s/failureDomains/%s", clustersBasePath, cluster, domain)
+}
+
+// FailureDomains gives access to the failure domains defined within a cluster
+type FailureDomains interface {
+	// List returns all the failure domains of a cluster, indexed by name
+	List(ctx context.Context, cluster string) (map[string]FailureDomain, error)
+
+	// Get returns a single failure domain
+	Get(ctx context.Context, cluster string, domain string) (FailureDomain, error)
+
+	// Create defines a new failure domain in the cluster
+	Create(ctx context.Context, cluster string, domain string, failureDomain FailureDomain) error
+
+	// Update replaces the brokers of an existing failure domain
+	Update(ctx context.Context, cluster string, domain string, failureDomain FailureDomain) error
+
+	// Delete removes a failure domain from the cluster
+	Delete(ctx context.Context, cluster string, domain string) error
+}
+
+type failureDomains struct {
+	client *Client
+}
+
+func (f *failureDomains) List(ctx context.Context, cluster string) (map[string]FailureDomain, error) {
+	domains := map[string]FailureDomain{}
+	err := f.client.RestGet(ctx, clusterFailureDomainsPath(cluster, ""), &domains)
+	return domains, err
+}
+
+func (f *failureDomains) Get(ctx context.Context, cluster string, domain string) (FailureDomain, error) {
+	failureDomain := FailureDomain{}
+	err := f.client.RestGet(ctx, clusterFailureDomainsPath(cluster, domain), &failureDomain)
+	return failureDomain, err
+}
+
+func (f *failureDomains) Create(ctx context.Context, cluster string, domain string, failureDomain FailureDomain) error {
+	return f.client.RestPost(ctx, clusterFailureDomainsPath(cluster, domain), failureDomain)
+}
+
+func (f *failureDomains) Update(ctx context.Context, cluster string, domain string, failureDomain FailureDomain) error {
+	return f.client.RestPost(ctx, clusterFailureDomainsPath(cluster, domain), failureDomain)
+}
+
+func (f *failureDomains) Delete(ctx context.Context, cluster string, domain string) error {
+	return f.client.RestDelete(ctx, clusterFailureDomainsPath(cluster, domain))
+}
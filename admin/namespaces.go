@@ -0,0 +1,260 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/merlimat/pulsar-ctl/names"
+)
+
+// Policies holds the settings of a namespace
+type Policies struct {
+	AuthPolicies                AuthPolicies            `json:"auth_policies"`
+	ReplicationClusters         []string                `json:"replication_clusters"`
+	Bundles                     *BundlesData            `json:"bundles"`
+	BacklogQuotaMap             map[string]BacklogQuota `json:"backlog_quota_map"`
+	RetentionPolicies           *RetentionPolicies      `json:"retention_policies"`
+	DeduplicationEnabled        *bool                   `json:"deduplicationEnabled"`
+	MessageTTLInSeconds         int                     `json:"message_ttl_in_seconds"`
+	Deleted                     bool                    `json:"deleted"`
+	AntiAffinityGroup           string                  `json:"antiAffinityGroup,omitempty"`
+	EncryptionRequired          bool                    `json:"encryption_required"`
+	SubscriptionAuthMode        string                  `json:"subscription_auth_mode"`
+	MaxProducersPerTopic        int                     `json:"max_producers_per_topic"`
+	MaxConsumersPerTopic        int                     `json:"max_consumers_per_topic"`
+	MaxConsumersPerSubscription int                     `json:"max_consumers_per_subscription"`
+	CompactionThreshold         int64                   `json:"compaction_threshold"`
+	OffloadThreshold            int64                   `json:"offload_threshold"`
+	SchemaValidationEnforced    bool                    `json:"schema_validation_enforced"`
+}
+
+// AuthPolicies holds the actions allowed to each role, on a namespace and on
+// its topics
+type AuthPolicies struct {
+	NamespaceAuth         map[string][]string            `json:"namespace_auth"`
+	DestinationAuth       map[string]map[string][]string `json:"destination_auth"`
+	SubscriptionAuthRoles map[string][]string            `json:"subscription_auth_roles"`
+}
+
+// Actions that can be granted to a role on a namespace or a topic
+const (
+	ActionProduce   = "produce"
+	ActionConsume   = "consume"
+	ActionFunctions = "functions"
+	ActionSources   = "sources"
+	ActionSinks     = "sinks"
+	ActionPackages  = "packages"
+)
+
+// BundlesData describes how the hash range of a namespace is split in
+// bundles, the unit of load balancing between the brokers
+type BundlesData struct {
+	Boundaries []string `json:"boundaries,omitempty"`
+	NumBundles int      `json:"numBundles"`
+}
+
+// RetentionPolicies sets how long and how much of the acknowledged messages
+// are kept. -1 means no limit.
+type RetentionPolicies struct {
+	RetentionTimeInMinutes int   `json:"retentionTimeInMinutes"`
+	RetentionSizeInMB      int64 `json:"retentionSizeInMB"`
+}
+
+// BacklogQuota limits the size of the backlog of the topics of a namespace,
+// and sets what happens when it's exceeded
+type BacklogQuota struct {
+	Limit  int64  `json:"limit"`
+	Policy string `json:"policy"`
+}
+
+// Type of backlog quota, the only one supported by the brokers
+const DestinationStorage = "destination_storage"
+
+// Policies applied when a backlog quota is exceeded
+const (
+	// The producers are blocked until there is space in the backlog
+	ProducerRequestHold = "producer_request_hold"
+
+	// The producers get an error
+	ProducerException = "producer_exception"
+
+	// The oldest messages of the backlog are discarded
+	ConsumerBacklogEviction = "consumer_backlog_eviction"
+)
+
+const (
+	namespacesBasePath = "/admin/v2/namespaces"
+)
+
+// Namespaces gives access to the namespaces of the tenants. The namespaces
+// are given parsed, to tell the legacy ones of Pulsar 1.x, in the form
+// <tenant>/<cluster>/<namespace>, which are only served by the v1 admin API.
+type Namespaces interface {
+	// List returns the names of the namespaces of a tenant
+	List(ctx context.Context, tenant string) ([]string, error)
+
+	// Create creates a namespace with the given number of bundles, or the
+	// default of the brokers if 0, replicated in the given clusters, or
+	// only in the local one if empty. The legacy namespaces belong to the
+	// cluster in their name, so clusters must be empty.
+	Create(ctx context.Context, namespace *names.NamespaceName, numBundles int, clusters []string) error
+
+	// Policies returns the settings of a namespace
+	Policies(ctx context.Context, namespace *names.NamespaceName) (Policies, error)
+
+	// Delete removes a namespace. The namespace must not have any topic.
+	Delete(ctx context.Context, namespace *names.NamespaceName) error
+
+	// GetRetention returns the retention of a namespace, or nil if it's not
+	// set and the default of the brokers applies
+	GetRetention(ctx context.Context, namespace *names.NamespaceName) (*RetentionPolicies, error)
+
+	// SetRetention sets the retention of a namespace
+	SetRetention(ctx context.Context, namespace *names.NamespaceName, retention RetentionPolicies) error
+
+	// GetBacklogQuotas returns the backlog quotas of a namespace, indexed by
+	// type
+	GetBacklogQuotas(ctx context.Context, namespace *names.NamespaceName) (map[string]BacklogQuota, error)
+
+	// SetBacklogQuota sets the destination storage quota of a namespace
+	SetBacklogQuota(ctx context.Context, namespace *names.NamespaceName, quota BacklogQuota) error
+
+	// RemoveBacklogQuota removes the destination storage quota of a
+	// namespace, so that the default of the brokers applies
+	RemoveBacklogQuota(ctx context.Context, namespace *names.NamespaceName) error
+
+	// GetReplicationClusters returns the clusters a namespace is replicated in
+	GetReplicationClusters(ctx context.Context, namespace *names.NamespaceName) ([]string, error)
+
+	// SetReplicationClusters sets the clusters a namespace is replicated in.
+	// The tenant must be allowed to use all of them.
+	SetReplicationClusters(ctx context.Context, namespace *names.NamespaceName, clusters []string) error
+
+	// Permissions returns the actions allowed to each role on a namespace
+	Permissions(ctx context.Context, namespace *names.NamespaceName) (map[string][]string, error)
+
+	// GrantPermission allows the actions to a role on a namespace, replacing
+	// the ones it was allowed before
+	GrantPermission(ctx context.Context, namespace *names.NamespaceName, role string, actions []string) error
+
+	// RevokePermission removes all the permissions of a role on a namespace
+	RevokePermission(ctx context.Context, namespace *names.NamespaceName, role string) error
+}
+
+type namespaces struct {
+	client *Client
+}
+
+func (n *namespaces) List(ctx context.Context, tenant string) ([]string, error) {
+	return n.client.RestGetStringList(ctx, namespacesBasePath+"/"+tenant)
+}
+
+func (n *namespaces) Create(ctx context.Context, namespace *names.NamespaceName, numBundles int, clusters []string) error {
+	var bundles *BundlesData
+	if numBundles > 0 {
+		bundles = &BundlesData{NumBundles: numBundles}
+	}
+
+	// The v1 API only takes the bundles, the cluster is in the name
+	if namespace.Cluster() != "" {
+		if len(clusters) > 0 {
+			return fmt.Errorf("the clusters of the legacy namespace %s can't be set", namespace)
+		}
+		if bundles == nil {
+			return n.client.RestPut(ctx, namespace.AdminPath(), nil)
+		}
+		return n.client.RestPut(ctx, namespace.AdminPath(), bundles)
+	}
+
+	policies := struct {
+		Bundles             *BundlesData `json:"bundles,omitempty"`
+		ReplicationClusters []string     `json:"replication_clusters,omitempty"`
+	}{Bundles: bundles, ReplicationClusters: clusters}
+	return n.client.RestPut(ctx, namespace.AdminPath(), policies)
+}
+
+func (n *namespaces) Policies(ctx context.Context, namespace *names.NamespaceName) (Policies, error) {
+	policies := Policies{}
+	err := n.client.RestGet(ctx, namespace.AdminPath(), &policies)
+	return policies, err
+}
+
+func (n *namespaces) Delete(ctx context.Context, namespace *names.NamespaceName) error {
+	return n.client.RestDelete(ctx, namespace.AdminPath())
+}
+
+func (n *namespaces) GetRetention(ctx context.Context, namespace *names.NamespaceName) (*RetentionPolicies, error) {
+	// The brokers answer with an empty body when the retention is not set
+	path := namespace.AdminPath() + "/retention"
+	body, err := n.client.RestCall(ctx, http.MethodGet, path, nil)
+	if err != nil || len(body) == 0 {
+		return nil, err
+	}
+
+	var retention *RetentionPolicies
+	if err := json.Unmarshal(body, &retention); err != nil {
+		return nil, fmt.Errorf("failed to decode response from %s: %s", path, err)
+	}
+	return retention, nil
+}
+
+func (n *namespaces) SetRetention(ctx context.Context, namespace *names.NamespaceName, retention RetentionPolicies) error {
+	return n.client.RestPost(ctx, namespace.AdminPath()+"/retention", retention)
+}
+
+func (n *namespaces) GetBacklogQuotas(ctx context.Context, namespace *names.NamespaceName) (map[string]BacklogQuota, error) {
+	quotas := map[string]BacklogQuota{}
+	err := n.client.RestGet(ctx, namespace.AdminPath()+"/backlogQuotaMap", &quotas)
+	return quotas, err
+}
+
+func (n *namespaces) SetBacklogQuota(ctx context.Context, namespace *names.NamespaceName, quota BacklogQuota) error {
+	return n.client.RestPost(ctx, backlogQuotaPath(namespace), quota)
+}
+
+func (n *namespaces) RemoveBacklogQuota(ctx context.Context, namespace *names.NamespaceName) error {
+	return n.client.RestDelete(ctx, backlogQuotaPath(namespace))
+}
+
+func backlogQuotaPath(namespace *names.NamespaceName) string {
+	return namespace.AdminPath() + "/backlogQuota?backlogQuotaType=" + DestinationStorage
+}
+
+func (n *namespaces) GetReplicationClusters(ctx context.Context, namespace *names.NamespaceName) ([]string, error) {
+	return n.client.RestGetStringList(ctx, namespace.AdminPath()+"/replication")
+}
+
+func (n *namespaces) SetReplicationClusters(ctx context.Context, namespace *names.NamespaceName, clusters []string) error {
+	return n.client.RestPost(ctx, namespace.AdminPath()+"/replication", clusters)
+}
+
+func (n *namespaces) Permissions(ctx context.Context, namespace *names.NamespaceName) (map[string][]string, error) {
+	permissions := map[string][]string{}
+	err := n.client.RestGet(ctx, namespace.AdminPath()+"/permissions", &permissions)
+	return permissions, err
+}
+
+func (n *namespaces) GrantPermission(ctx context.Context, namespace *names.NamespaceName, role string, actions []string) error {
+	return n.client.RestPost(ctx, namespace.AdminPath()+"/permissions/"+url.PathEscape(role), actions)
+}
+
+func (n *namespaces) RevokePermission(ctx context.Context, namespace *names.NamespaceName, role string) error {
+	return n.client.RestDelete(ctx, namespace.AdminPath()+"/permissions/"+url.PathEscape(role))
+}
@@ -0,0 +1,285 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth2Config holds the settings of the OAuth2 client credentials flow, like
+// the ones of Pulsar's AuthenticationOAuth2 plugin
+type OAuth2Config struct {
+	// URL of the authorization server. The token endpoint is discovered from
+	// its /.well-known/openid-configuration. If empty, the issuer_url of the
+	// credentials file is used.
+	IssuerUrl string
+
+	// Audience and scope requested for the access token
+	Audience string
+	Scope    string
+
+	// Credentials file, in JSON with client_id, client_secret and optionally
+	// issuer_url. It can be a path, a file:// URL or a
+	// data:application/json;base64, URL.
+	PrivateKey string
+
+	// Directory where the access tokens are cached until they expire, so that
+	// they are reused across invocations. Empty disables the cache.
+	CacheDir string
+
+	// HTTP client used to talk to the authorization server. Defaults to a
+	// client with the same TLS settings as the admin requests, eg: to trust
+	// the private CA of the issuer.
+	HTTPClient *http.Client
+}
+
+// oauth2Credentials is the content of the credentials file
+type oauth2Credentials struct {
+	Type         string `json:"type"`
+	ClientId     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	IssuerUrl    string `json:"issuer_url"`
+}
+
+// oauth2TokenSource obtains the access tokens with the client credentials
+// grant, and reuses them until they expire
+type oauth2TokenSource struct {
+	issuerUrl   string
+	audience    string
+	scope       string
+	credentials oauth2Credentials
+	cache       tokenCache
+	httpClient  *http.Client
+
+	lock    sync.Mutex
+	current *cachedToken
+}
+
+func newOAuth2TokenSource(config *OAuth2Config, tlsConfig *tls.Config) (*oauth2TokenSource, error) {
+	if config.PrivateKey == "" {
+		return nil, errors.New("the OAuth2 private key is not set")
+	}
+
+	credentials, err := readOAuth2Credentials(config.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	issuerUrl := config.IssuerUrl
+	if issuerUrl == "" {
+		issuerUrl = credentials.IssuerUrl
+	}
+	if issuerUrl == "" {
+		return nil, errors.New("the OAuth2 issuer URL is not set")
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil && tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		httpClient = &http.Client{Transport: transport}
+	} else if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	s := &oauth2TokenSource{
+		issuerUrl:   strings.TrimSuffix(issuerUrl, "/"),
+		audience:    config.Audience,
+		scope:       config.Scope,
+		credentials: *credentials,
+		httpClient:  httpClient,
+	}
+
+	// The tokens are cached per client and requested access
+	s.cache = newTokenCache(config.CacheDir, s.issuerUrl, credentials.ClientId, s.audience, s.scope)
+	return s, nil
+}
+
+func readOAuth2Credentials(privateKey string) (*oauth2Credentials, error) {
+	var data []byte
+	var err error
+
+	switch {
+	case strings.HasPrefix(privateKey, "data:"):
+		comma := strings.IndexByte(privateKey, ',')
+		if comma < 0 || !strings.HasSuffix(privateKey[:comma], ";base64") {
+			return nil, errors.New("the OAuth2 private key data URL must be base64 encoded")
+		}
+		data, err = base64.StdEncoding.DecodeString(privateKey[comma+1:])
+	case strings.HasPrefix(privateKey, "file://"):
+		data, err = ioutil.ReadFile(strings.TrimPrefix(privateKey, "file://"))
+	default:
+		data, err = ioutil.ReadFile(privateKey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the OAuth2 credentials: %s", err)
+	}
+
+	credentials := &oauth2Credentials{}
+	if err := json.Unmarshal(data, credentials); err != nil {
+		return nil, fmt.Errorf("failed to parse the OAuth2 credentials: %s", err)
+	}
+	if credentials.ClientId == "" || credentials.ClientSecret == "" {
+		return nil, errors.New("the OAuth2 credentials must have a client_id and a client_secret")
+	}
+	return credentials, nil
+}
+
+func (s *oauth2TokenSource) token(ctx context.Context) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.current.valid() {
+		return s.current.Token, nil
+	}
+
+	if cached := s.cache.read(); cached.valid() {
+		s.current = cached
+		return cached.Token, nil
+	}
+
+	token, err := s.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.current = token
+	s.cache.write(token)
+	return token.Token, nil
+}
+
+func (s *oauth2TokenSource) invalidate() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.current = nil
+	s.cache.remove()
+	return true
+}
+
+// requestToken performs the client credentials grant against the token
+// endpoint of the issuer
+func (s *oauth2TokenSource) requestToken(ctx context.Context) (*cachedToken, error) {
+	tokenEndpoint, err := s.discoverTokenEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", s.credentials.ClientId)
+	form.Set("client_secret", s.credentials.ClientSecret)
+	if s.audience != "" {
+		form.Set("audience", s.audience)
+	}
+	if s.scope != "" {
+		form.Set("scope", s.scope)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var response struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	status, err := s.do(ctx, req, &response)
+	if err != nil {
+		return nil, fmt.Errorf("OAuth2 token request to %s failed: %s", tokenEndpoint, err)
+	}
+
+	if status != http.StatusOK || response.AccessToken == "" {
+		reason := response.ErrorDescription
+		if reason == "" {
+			reason = response.Error
+		}
+		if reason == "" {
+			reason = http.StatusText(status)
+		}
+		return nil, fmt.Errorf("OAuth2 token request to %s failed: %s (HTTP %d)", tokenEndpoint, reason, status)
+	}
+
+	token := &cachedToken{Token: response.AccessToken}
+	if response.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	} else {
+		// Without an expiration, assume a short lifetime
+		token.Expiry = time.Now().Add(tokenExpiryMargin + time.Minute)
+	}
+	return token, nil
+}
+
+func (s *oauth2TokenSource) discoverTokenEndpoint(ctx context.Context) (string, error) {
+	wellKnown := s.issuerUrl + "/.well-known/openid-configuration"
+	req, err := http.NewRequest(http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var metadata struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+
+	status, err := s.do(ctx, req, &metadata)
+	if err != nil {
+		return "", fmt.Errorf("OAuth2 discovery from %s failed: %s", wellKnown, err)
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("OAuth2 discovery from %s failed: %s (HTTP %d)", wellKnown, http.StatusText(status), status)
+	}
+	if metadata.TokenEndpoint == "" {
+		return "", fmt.Errorf("OAuth2 discovery from %s failed: no token_endpoint", wellKnown)
+	}
+	return metadata.TokenEndpoint, nil
+}
+
+// do sends a request to the authorization server and decodes the JSON
+// response into obj, whatever the status code
+func (s *oauth2TokenSource) do(ctx context.Context, req *http.Request, obj interface{}) (int, error) {
+	resp, err := s.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := json.Unmarshal(body, obj); err != nil && resp.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("invalid response: %s", err)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,281 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// authorizationServer is a fake OAuth2 authorization server, issuing the
+// tokens access-1, access-2, etc, in turn
+type authorizationServer struct {
+	*httptest.Server
+	expiresIn int
+
+	lock  sync.Mutex
+	forms []url.Values
+}
+
+func newAuthorizationServer(t *testing.T, expiresIn int) *authorizationServer {
+	s := &authorizationServer{expiresIn: expiresIn}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *authorizationServer) serve(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		json.NewEncoder(w).Encode(map[string]string{"token_endpoint": s.URL + "/oauth/token"})
+
+	case "/oauth/token":
+		if r.Method != http.MethodPost || r.ParseForm() != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.lock.Lock()
+		s.forms = append(s.forms, r.PostForm)
+		n := len(s.forms)
+		s.lock.Unlock()
+
+		if r.PostForm.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client", "error_description": "wrong secret"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("access-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   s.expiresIn,
+		})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// requests returns the forms of the token requests received so far
+func (s *authorizationServer) requests() []url.Values {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]url.Values{}, s.forms...)
+}
+
+// privateKey returns the credentials as a data URL
+func (s *authorizationServer) privateKey(secret string) string {
+	credentials := fmt.Sprintf(`{"type": "client_credentials", "client_id": "pulsar-ctl", "client_secret": "%s", "issuer_url": "%s"}`,
+		secret, s.URL)
+	return "data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(credentials))
+}
+
+func TestOAuth2(t *testing.T) {
+	issuer := newAuthorizationServer(t, 3600)
+	var last atomic.Value
+	client := newTestClient(t, &Config{OAuth2: &OAuth2Config{
+		PrivateKey: issuer.privateKey("secret"),
+		Audience:   "urn:pulsar:cluster",
+		Scope:      "admin",
+	}}, authorizationRecorder("Bearer access-1", &last))
+
+	// The token is reused until it expires
+	for i := 0; i < 3; i++ {
+		if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+			t.Fatalf("unexpected error: %s, sent Authorization: %v", err, last.Load())
+		}
+	}
+
+	requests := issuer.requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d token requests, expected 1", len(requests))
+	}
+	expected := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {"pulsar-ctl"},
+		"client_secret": {"secret"},
+		"audience":      {"urn:pulsar:cluster"},
+		"scope":         {"admin"},
+	}
+	if requests[0].Encode() != expected.Encode() {
+		t.Errorf("got token request %s, expected %s", requests[0].Encode(), expected.Encode())
+	}
+}
+
+func TestOAuth2Expiry(t *testing.T) {
+	// The tokens expire within the margin, so they are never reused
+	issuer := newAuthorizationServer(t, int(tokenExpiryMargin.Seconds())/2)
+	source, err := newOAuth2TokenSource(&OAuth2Config{PrivateKey: issuer.privateKey("secret")}, nil)
+	if err != nil {
+		t.Fatalf("newOAuth2TokenSource: %s", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		token, err := source.token(context.Background())
+		if expected := fmt.Sprintf("access-%d", i); err != nil || token != expected {
+			t.Errorf("got token %s, %v, expected %s", token, err, expected)
+		}
+	}
+}
+
+func TestOAuth2Invalidate(t *testing.T) {
+	issuer := newAuthorizationServer(t, 3600)
+	var last atomic.Value
+	client := newTestClient(t, &Config{OAuth2: &OAuth2Config{PrivateKey: issuer.privateKey("secret")}},
+		authorizationRecorder("Bearer access-2", &last))
+
+	// The broker rejects the first token, eg: revoked before its expiry
+	if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+		t.Fatalf("unexpected error: %s, sent Authorization: %v", err, last.Load())
+	}
+	if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+		t.Fatalf("unexpected error: %s, sent Authorization: %v", err, last.Load())
+	}
+	if requests := issuer.requests(); len(requests) != 2 {
+		t.Errorf("got %d token requests, expected 2", len(requests))
+	}
+}
+
+func TestOAuth2Cache(t *testing.T) {
+	issuer := newAuthorizationServer(t, 3600)
+	config := &OAuth2Config{PrivateKey: issuer.privateKey("secret"), CacheDir: t.TempDir()}
+
+	// The token is cached across sources, like across invocations
+	for i := 0; i < 2; i++ {
+		source, err := newOAuth2TokenSource(config, nil)
+		if err != nil {
+			t.Fatalf("newOAuth2TokenSource: %s", err)
+		}
+		if token, err := source.token(context.Background()); err != nil || token != "access-1" {
+			t.Errorf("got token %s, %v, expected access-1", token, err)
+		}
+	}
+
+	// But not for another audience
+	config.Audience = "other"
+	source, err := newOAuth2TokenSource(config, nil)
+	if err != nil {
+		t.Fatalf("newOAuth2TokenSource: %s", err)
+	}
+	if token, err := source.token(context.Background()); err != nil || token != "access-2" {
+		t.Errorf("got token %s, %v, expected access-2", token, err)
+	}
+}
+
+func TestOAuth2Errors(t *testing.T) {
+	issuer := newAuthorizationServer(t, 3600)
+
+	for _, test := range []struct {
+		config   OAuth2Config
+		expected string
+	}{
+		{OAuth2Config{PrivateKey: issuer.privateKey("wrong")}, "wrong secret (HTTP 401)"},
+		{OAuth2Config{PrivateKey: issuer.privateKey("secret"), IssuerUrl: issuer.URL + "/missing"},
+			"OAuth2 discovery from " + issuer.URL + "/missing/.well-known/openid-configuration failed"},
+	} {
+		var requests int32
+		client := newTestClient(t, &Config{OAuth2: &test.config, MaxRetries: 3}, statusSequence(&requests))
+
+		err := client.RestGet(context.Background(), "/admin/v2/clusters", nil)
+		if _, ok := err.(*AuthError); !ok || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("got error %v, expected an AuthError with %q", err, test.expected)
+		}
+		if sent := atomic.LoadInt32(&requests); sent != 0 {
+			t.Errorf("%d requests were sent without credentials", sent)
+		}
+	}
+}
+
+func TestOAuth2Credentials(t *testing.T) {
+	credentials := `{"client_id": "pulsar-ctl", "client_secret": "secret", "issuer_url": "https://auth.example.com"}`
+	credentialsFile := filepath.Join(t.TempDir(), "credentials.json")
+	if err := ioutil.WriteFile(credentialsFile, []byte(credentials), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, privateKey := range []string{
+		credentialsFile,
+		"file://" + credentialsFile,
+		"data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(credentials)),
+	} {
+		source, err := newOAuth2TokenSource(&OAuth2Config{PrivateKey: privateKey}, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", privateKey, err)
+		} else if source.credentials.ClientId != "pulsar-ctl" || source.issuerUrl != "https://auth.example.com" {
+			t.Errorf("%s: got %+v from %s", privateKey, source.credentials, source.issuerUrl)
+		}
+	}
+
+	for _, privateKey := range []string{
+		"",
+		filepath.Join(t.TempDir(), "missing.json"),
+		"data:application/json," + credentials,
+		"data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(`{"client_id": "pulsar-ctl"}`)),
+		"data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(`{"client_id": "id", "client_secret": "secret"}`)),
+	} {
+		if _, err := newOAuth2TokenSource(&OAuth2Config{PrivateKey: privateKey}, nil); err == nil {
+			t.Errorf("%s: expected an error", privateKey)
+		}
+	}
+}
+
+func TestOAuth2TLS(t *testing.T) {
+	// An issuer with a certificate signed by a private CA
+	issuer := &authorizationServer{expiresIn: 3600}
+	issuer.Server = httptest.NewTLSServer(http.HandlerFunc(issuer.serve))
+	t.Cleanup(issuer.Close)
+
+	certFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuer.Certificate().Raw})
+	if err := ioutil.WriteFile(certFile, cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The issuer is reached with the TLS settings of the admin client
+	for _, test := range []struct {
+		name   string
+		config Config
+		ok     bool
+	}{
+		{"untrusted", Config{}, false},
+		{"trusted", Config{TLSTrustCertsFilePath: certFile}, true},
+		{"insecure", Config{TLSAllowInsecureConnection: true}, true},
+	} {
+		test.config.OAuth2 = &OAuth2Config{PrivateKey: issuer.privateKey("secret")}
+		var last atomic.Value
+		expected := fmt.Sprintf("Bearer access-%d", len(issuer.requests())+1)
+		client := newTestClient(t, &test.config, authorizationRecorder(expected, &last))
+
+		err := client.RestGet(context.Background(), "/admin/v2/clusters", nil)
+		if test.ok && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+		if !test.ok && (err == nil || !strings.Contains(err.Error(), "certificate")) {
+			t.Errorf("%s: expected a TLS error, got %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gopkg.in/resty.v1"
+)
+
+func (c *Client) prepareRequest(ctx context.Context) (*resty.Request, error) {
+	var r = c.rest.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Accept", "application/json").
+		SetHeader("User-Agent", c.userAgent)
+
+	if c.auth != nil {
+		if err := c.auth.authenticate(ctx, r); err != nil {
+			return nil, &AuthError{Err: err}
+		}
+	}
+	return r, nil
+}
+
+// execute sends a request to the given path, retrying on transient failures,
+// and checks that the response has the expected status code, or any 2xx
+// status if expectedStatus is 0
+func (c *Client) execute(ctx context.Context, method string, path string, content interface{}, expectedStatus int) (*resty.Response, error) {
+	if c.dryRun && method != http.MethodGet {
+		return nil, c.printDryRun(method, path, content)
+	}
+
+	var deadline time.Time
+	if c.retryTimeout > 0 {
+		deadline = time.Now().Add(c.retryTimeout)
+	}
+
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, content)
+		if err == nil && (resp.StatusCode() == expectedStatus || expectedStatus == 0 && resp.StatusCode()/100 == 2) {
+			return resp, nil
+		}
+
+		// Sending the request again won't fix the credentials
+		if _, ok := err.(*AuthError); ok {
+			return nil, err
+		}
+
+		// The token may have expired or been revoked before its expiration
+		// time: get a new one and send the request again, once. This does
+		// not count as a retry.
+		if err == nil && resp.StatusCode() == http.StatusUnauthorized && !reauthenticated &&
+			c.auth != nil && c.auth.invalidate() {
+			reauthenticated = true
+			attempt--
+			continue
+		}
+
+		if attempt < c.maxRetries && ctx.Err() == nil && c.isRetryable(method, resp, err) {
+			if wait, ok := retryWait(attempt, resp, deadline); ok {
+				select {
+				case <-time.After(wait):
+					continue
+				case <-ctx.Done():
+					return nil, &Error{Method: method, Path: path, Err: ctx.Err()}
+				}
+			}
+		}
+
+		if err != nil {
+			return nil, &Error{Method: method, Path: path, Err: err}
+		}
+		return nil, responseError(method, path, resp)
+	}
+}
+
+// send sends a request to the admin service URLs in turn, until one of them is
+// reachable and available. The URL that answered is remembered, so that the
+// next requests go to it first.
+func (c *Client) send(ctx context.Context, method string, path string, content interface{}) (*resty.Response, error) {
+	urls := c.serviceUrls.urls
+	first := c.serviceUrls.first()
+
+	var resp *resty.Response
+	var err error
+	for i := 0; i < len(urls); i++ {
+		index := (first + i) % len(urls)
+
+		var r *resty.Request
+		if r, err = c.prepareRequest(ctx); err != nil {
+			return nil, err
+		}
+		if content != nil {
+			r.SetBody(content)
+		}
+
+		c.traceRequest(r, method, urls[index]+path, content)
+		start := time.Now()
+		resp, err = r.Execute(method, urls[index]+path)
+		c.traceResponse(resp, err, time.Since(start))
+
+		if ctx.Err() != nil {
+			return resp, err
+		}
+
+		unavailable := err != nil || resp.StatusCode() == http.StatusServiceUnavailable
+		if !unavailable {
+			c.serviceUrls.markHealthy(index)
+			return resp, nil
+		}
+	}
+
+	return resp, err
+}
+
+// RestGet performs a GET request on the given path and decodes the JSON
+// response into obj
+func (c *Client) RestGet(ctx context.Context, path string, obj interface{}) error {
+	resp, err := c.execute(ctx, http.MethodGet, path, nil, 200)
+	if err != nil {
+		return err
+	}
+
+	if obj == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(resp.Body(), obj); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %s", path, err)
+	}
+	return nil
+}
+
+// RestPut performs a PUT request on the given path, with content encoded
+// as JSON
+func (c *Client) RestPut(ctx context.Context, path string, content interface{}) error {
+	_, err := c.execute(ctx, http.MethodPut, path, content, 204)
+	return err
+}
+
+// RestPost performs a POST request on the given path, with content encoded
+// as JSON. The request is only retried if RetryPost is set in the config.
+func (c *Client) RestPost(ctx context.Context, path string, content interface{}) error {
+	_, err := c.execute(ctx, http.MethodPost, path, content, 204)
+	return err
+}
+
+// RestDelete performs a DELETE request on the given path
+func (c *Client) RestDelete(ctx context.Context, path string) error {
+	_, err := c.execute(ctx, http.MethodDelete, path, nil, 204)
+	return err
+}
+
+// RestGetStringList performs a GET request on a path that returns a JSON
+// array of strings
+func (c *Client) RestGetStringList(ctx context.Context, path string) ([]string, error) {
+	var list []string
+	if err := c.RestGet(ctx, path, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// RestCall performs a request with any method on any admin path, eg:
+// /admin/v2/brokers/health, with the body sent as it is. Any 2xx response is
+// successful and its body is returned. In dry-run mode, the requests other
+// than GET are not sent and the returned body is nil.
+func (c *Client) RestCall(ctx context.Context, method string, path string, body []byte) ([]byte, error) {
+	var content interface{}
+	if len(body) > 0 {
+		content = body
+	}
+
+	resp, err := c.execute(ctx, method, path, content, 0)
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return resp.Body(), nil
+}
+
+// printDryRun describes the request that would be sent, instead of sending it
+func (c *Client) printDryRun(method string, path string, content interface{}) error {
+	if _, err := fmt.Fprintf(c.dryRunOutput, "%s %s\n", method, c.WebServiceUrl()+path); err != nil {
+		return err
+	}
+
+	if content == nil {
+		return nil
+	}
+
+	body, err := encodeBody(content)
+	if err != nil {
+		return err
+	}
+
+	var indented bytes.Buffer
+	if json.Indent(&indented, body, "", "   ") == nil {
+		body = indented.Bytes()
+	}
+	_, err = fmt.Fprintln(c.dryRunOutput, string(body))
+	return err
+}
+
+// encodeBody returns the body of a request like it's sent: the raw bodies as
+// they are and any other content encoded as JSON
+func encodeBody(content interface{}) ([]byte, error) {
+	switch content := content.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return content, nil
+	default:
+		return json.Marshal(content)
+	}
+}
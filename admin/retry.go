@@ -0,0 +1,106 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gopkg.in/resty.v1"
+)
+
+const (
+	retryInitialBackoff = 200 * time.Millisecond
+	retryMaxBackoff     = 10 * time.Second
+)
+
+// isRetryable tells whether a failed request can be attempted again: the
+// failure must be transient and the request must be safe to repeat
+func (c *Client) isRetryable(method string, resp *resty.Response, err error) bool {
+	if method == http.MethodPost && !c.retryPost {
+		return false
+	}
+
+	if err != nil {
+		// Connection refused or reset, eg: while a broker restarts
+		return true
+	}
+
+	// Other 5xx statuses, like 501 Not Implemented, won't change by sending
+	// the same request again
+	switch resp.StatusCode() {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryWait returns how long to wait before the next attempt, or false if
+// the attempt would start after the deadline. Without a deadline, the wait
+// is capped at retryMaxBackoff, even when the server asks for a longer one.
+func retryWait(attempt int, resp *resty.Response, deadline time.Time) (time.Duration, bool) {
+	wait := retryBackoff(attempt, resp)
+	if deadline.IsZero() {
+		if wait > retryMaxBackoff {
+			wait = retryMaxBackoff
+		}
+		return wait, true
+	}
+	return wait, time.Now().Add(wait).Before(deadline)
+}
+
+// retryBackoff returns how long to wait before the next attempt. The server
+// can ask for a delay through the Retry-After header, otherwise the delay
+// grows exponentially, with full jitter to spread the retries of concurrent
+// clients.
+func retryBackoff(attempt int, resp *resty.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header().Get("Retry-After")); ok {
+			return wait
+		}
+	}
+
+	backoff := retryMaxBackoff
+	if attempt < 16 {
+		if b := retryInitialBackoff << uint(attempt); b < retryMaxBackoff {
+			backoff = b
+		}
+	}
+	return time.Duration(rand.Int63n(int64(backoff)) + 1)
+}
+
+// retryAfter parses the Retry-After header, which is either a number of
+// seconds or an HTTP date
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
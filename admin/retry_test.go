@@ -0,0 +1,160 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/resty.v1"
+)
+
+func TestRetryTransientFailures(t *testing.T) {
+	for _, status := range []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	} {
+		var requests int32
+		client := newTestClient(t, &Config{MaxRetries: 3}, statusSequence(&requests, status, status))
+
+		err := client.RestGet(context.Background(), "/admin/v2/clusters", nil)
+		sent := atomic.LoadInt32(&requests)
+		if err != nil {
+			t.Errorf("HTTP %d: unexpected error: %s", status, err)
+		}
+		if sent != 3 {
+			t.Errorf("HTTP %d: got %d requests, expected 3", status, sent)
+		}
+	}
+}
+
+func TestRetryPermanentFailures(t *testing.T) {
+	for _, status := range []int{
+		http.StatusBadRequest,
+		http.StatusNotFound,
+		http.StatusConflict,
+		http.StatusNotImplemented,
+		http.StatusHTTPVersionNotSupported,
+	} {
+		var requests int32
+		client := newTestClient(t, &Config{MaxRetries: 3}, statusSequence(&requests, status))
+
+		err := client.RestGet(context.Background(), "/admin/v2/clusters", nil)
+		sent := atomic.LoadInt32(&requests)
+		if StatusCode(err) != status {
+			t.Errorf("HTTP %d: got error %v", status, err)
+		}
+		if sent != 1 {
+			t.Errorf("HTTP %d: got %d requests, expected 1", status, sent)
+		}
+	}
+}
+
+func TestRetryMaxRetries(t *testing.T) {
+	var requests int32
+	unavailable := http.StatusServiceUnavailable
+	client := newTestClient(t, &Config{MaxRetries: 2},
+		statusSequence(&requests, unavailable, unavailable, unavailable, unavailable))
+
+	err := client.RestGet(context.Background(), "/admin/v2/clusters", nil)
+	sent := atomic.LoadInt32(&requests)
+	if StatusCode(err) != unavailable {
+		t.Errorf("got error %v, expected HTTP %d", err, unavailable)
+	}
+	if sent != 3 {
+		t.Errorf("got %d requests, expected 3", sent)
+	}
+}
+
+func TestRetryPost(t *testing.T) {
+	for _, retryPost := range []bool{false, true} {
+		var requests int32
+		client := newTestClient(t, &Config{MaxRetries: 3, RetryPost: retryPost},
+			statusSequence(&requests, http.StatusServiceUnavailable, http.StatusNoContent))
+
+		err := client.RestPost(context.Background(), "/admin/v2/clusters/c1", nil)
+		sent := atomic.LoadInt32(&requests)
+		if retryPost && (err != nil || sent != 2) {
+			t.Errorf("RetryPost: got %d requests and error %v, expected 2 requests", sent, err)
+		}
+		if !retryPost && (err == nil || sent != 1) {
+			t.Errorf("no RetryPost: got %d requests and error %v, expected 1 request", sent, err)
+		}
+	}
+}
+
+func TestRetryAfterDeadline(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, &Config{MaxRetries: 3, RetryTimeout: time.Second},
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+	start := time.Now()
+	err := client.RestGet(context.Background(), "/admin/v2/clusters", nil)
+	sent := atomic.LoadInt32(&requests)
+	if StatusCode(err) != http.StatusServiceUnavailable {
+		t.Errorf("got error %v, expected HTTP 503", err)
+	}
+	if sent != 1 || time.Since(start) > time.Second {
+		t.Errorf("got %d requests in %s, expected to give up at once", sent, time.Since(start))
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	retryAfter := func(value string) *resty.Response {
+		return &resty.Response{RawResponse: &http.Response{Header: http.Header{"Retry-After": {value}}}}
+	}
+
+	for _, test := range []struct {
+		resp     *resty.Response
+		deadline time.Duration
+		expected time.Duration
+		ok       bool
+	}{
+		{retryAfter("5"), 0, 5 * time.Second, true},
+		{retryAfter("3600"), 0, retryMaxBackoff, true},
+		{retryAfter("3600"), time.Minute, time.Hour, false},
+		{retryAfter("3600"), 2 * time.Hour, time.Hour, true},
+	} {
+		var deadline time.Time
+		if test.deadline > 0 {
+			deadline = time.Now().Add(test.deadline)
+		}
+
+		wait, ok := retryWait(0, test.resp, deadline)
+		if wait != test.expected || ok != test.ok {
+			t.Errorf("Retry-After %s, deadline %s: got %s, %t, expected %s, %t",
+				test.resp.Header().Get("Retry-After"), test.deadline, wait, ok, test.expected, test.ok)
+		}
+	}
+
+	for attempt, max := range []time.Duration{retryInitialBackoff, 2 * retryInitialBackoff, 4 * retryInitialBackoff} {
+		if wait, ok := retryWait(attempt, nil, time.Time{}); wait <= 0 || wait > max || !ok {
+			t.Errorf("attempt %d: got %s, %t, expected up to %s", attempt, wait, ok, max)
+		}
+	}
+	if wait, _ := retryWait(100, nil, time.Time{}); wait <= 0 || wait > retryMaxBackoff {
+		t.Errorf("attempt 100: got %s, expected up to %s", wait, retryMaxBackoff)
+	}
+}
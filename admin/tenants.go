@@ -0,0 +1,74 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import "context"
+
+type TenantInfo struct {
+	AdminRoles      []string `json:"adminRoles"`
+	AllowedClusters []string `json:"allowedClusters"`
+}
+
+const (
+	tenantsBasePath = "/admin/v2/tenants"
+)
+
+func tenantPath(name string) string {
+	return tenantsBasePath + "/" + name
+}
+
+// Tenants gives access to the Pulsar tenants
+type Tenants interface {
+	// List returns the names of all the tenants
+	List(ctx context.Context) ([]string, error)
+
+	// Get returns the configuration of a tenant
+	Get(ctx context.Context, name string) (TenantInfo, error)
+
+	// Create creates a new tenant
+	Create(ctx context.Context, name string, tenant TenantInfo) error
+
+	// Update replaces the configuration of an existing tenant
+	Update(ctx context.Context, name string, tenant TenantInfo) error
+
+	// Delete removes a tenant. The tenant must not have any namespace.
+	Delete(ctx context.Context, name string) error
+}
+
+type tenants struct {
+	client *Client
+}
+
+func (t *tenants) List(ctx context.Context) ([]string, error) {
+	return t.client.RestGetStringList(ctx, tenantsBasePath)
+}
+
+func (t *tenants) Get(ctx context.Context, name string) (TenantInfo, error) {
+	tenant := TenantInfo{}
+	err := t.client.RestGet(ctx, tenantPath(name), &tenant)
+	return tenant, err
+}
+
+func (t *tenants) Create(ctx context.Context, name string, tenant TenantInfo) error {
+	return t.client.RestPut(ctx, tenantPath(name), tenant)
+}
+
+func (t *tenants) Update(ctx context.Context, name string, tenant TenantInfo) error {
+	return t.client.RestPost(ctx, tenantPath(name), tenant)
+}
+
+func (t *tenants) Delete(ctx context.Context, name string) error {
+	return t.client.RestDelete(ctx, tenantPath(name))
+}
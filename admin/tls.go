@@ -0,0 +1,95 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+func newTLSConfig(config *Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	if config.TLSTrustCertsFilePath != "" {
+		pem, err := ioutil.ReadFile(config.TLSTrustCertsFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trusted certificates: %s", err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificate found in %s", config.TLSTrustCertsFilePath)
+		}
+	}
+
+	if config.TLSCertFile != "" || config.TLSKeyFile != "" {
+		if config.TLSCertFile == "" || config.TLSKeyFile == "" {
+			return nil, errors.New("both the TLS certificate and key files are required for TLS client authentication")
+		}
+
+		cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if config.TLSAllowInsecureConnection {
+		tlsConfig.InsecureSkipVerify = true
+	} else if !config.TLSEnableHostnameVerification && tlsConfig.RootCAs != nil {
+		// The certificates signed by a private CA often don't match the host
+		// names of the brokers. Go always checks the host name as part of the
+		// certificate verification, so we need to skip it and verify the
+		// chain ourselves. With the system roots, the host name is always
+		// verified.
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyCertificateChain(tlsConfig.RootCAs)
+	}
+
+	return tlsConfig, nil
+}
+
+// verifyCertificateChain checks the server certificate against the trusted
+// roots, without validating the host name
+func verifyCertificateChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("server did not present any TLS certificate")
+		}
+
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse server certificate: %s", err)
+			}
+			certs[i] = cert
+		}
+
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range certs[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+
+		_, err := certs[0].Verify(opts)
+		return err
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTLSServer starts an HTTPS server and returns it with the path of a file
+// holding its certificate, to be trusted by the clients
+func newTLSServer(t *testing.T) (*httptest.Server, string) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	t.Cleanup(server.Close)
+
+	certFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := ioutil.WriteFile(certFile, cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return server, certFile
+}
+
+func TestTLS(t *testing.T) {
+	server, certFile := newTLSServer(t)
+
+	// The certificate of the test server is valid for 127.0.0.1, not for
+	// localhost
+	localhost := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	for _, test := range []struct {
+		name   string
+		config Config
+		ok     bool
+	}{
+		{"untrusted", Config{WebServiceUrl: server.URL}, false},
+		{"trusted", Config{WebServiceUrl: server.URL, TLSTrustCertsFilePath: certFile}, true},
+		{"insecure", Config{WebServiceUrl: server.URL, TLSAllowInsecureConnection: true}, true},
+		{"other host name", Config{WebServiceUrl: localhost, TLSTrustCertsFilePath: certFile}, true},
+		{"host name verification", Config{WebServiceUrl: server.URL, TLSTrustCertsFilePath: certFile,
+			TLSEnableHostnameVerification: true}, true},
+		{"other host name verification", Config{WebServiceUrl: localhost, TLSTrustCertsFilePath: certFile,
+			TLSEnableHostnameVerification: true}, false},
+	} {
+		client, err := New(&test.config)
+		if err != nil {
+			t.Errorf("%s: New: %s", test.name, err)
+			continue
+		}
+
+		err = client.RestGet(context.Background(), "/admin/v2/clusters", nil)
+		if test.ok && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+		if !test.ok && err == nil {
+			t.Errorf("%s: expected a TLS error", test.name)
+		}
+	}
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	_, certFile := newTLSServer(t)
+
+	for _, config := range []Config{
+		{TLSTrustCertsFilePath: filepath.Join(t.TempDir(), "missing.pem")},
+		{TLSTrustCertsFilePath: "tls_test.go"},
+		{TLSCertFile: certFile},
+		{TLSKeyFile: certFile},
+		{TLSCertFile: certFile, TLSKeyFile: certFile},
+	} {
+		config.WebServiceUrl = "https://localhost:8443"
+		if _, err := New(&config); err == nil {
+			t.Errorf("New(%+v): expected an error", config)
+		}
+	}
+}
+
+// writeClientCertificate generates a self-signed client certificate and
+// returns the paths of the certificate and key files
+func writeClientCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "admin"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	certFile, keyFile := writeClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	pemData, _ := ioutil.ReadFile(certFile)
+	clientCAs.AppendCertsFromPEM(pemData)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	client, err := New(&Config{WebServiceUrl: server.URL, TLSAllowInsecureConnection: true,
+		TLSCertFile: certFile, TLSKeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// The certificate can be combined with another authentication method
+	client, err = New(&Config{WebServiceUrl: server.URL, TLSAllowInsecureConnection: true,
+		TLSCertFile: certFile, TLSKeyFile: keyFile, AuthToken: "token"})
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	client, err = New(&Config{WebServiceUrl: server.URL, TLSAllowInsecureConnection: true})
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+	if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err == nil {
+		t.Errorf("expected an error without the client certificate")
+	}
+}
+
+func TestTLSHostnameVerification(t *testing.T) {
+	_, certFile := newTLSServer(t)
+
+	// The host name is only skipped for the certificates signed by the
+	// trusted certificates, not by the system roots
+	for _, test := range []struct {
+		name   string
+		config Config
+		skip   bool
+	}{
+		{"system roots", Config{}, false},
+		{"system roots without verification", Config{TLSEnableHostnameVerification: false}, false},
+		{"trusted certificates", Config{TLSTrustCertsFilePath: certFile}, true},
+		{"trusted certificates with verification", Config{TLSTrustCertsFilePath: certFile,
+			TLSEnableHostnameVerification: true}, false},
+	} {
+		tlsConfig, err := newTLSConfig(&test.config)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if skipped := tlsConfig.InsecureSkipVerify && tlsConfig.VerifyPeerCertificate != nil; skipped != test.skip {
+			t.Errorf("%s: host name verification skipped: %v, expected %v", test.name, skipped, test.skip)
+		}
+		if tlsConfig.InsecureSkipVerify && tlsConfig.VerifyPeerCertificate == nil {
+			t.Errorf("%s: the certificates are not verified", test.name)
+		}
+	}
+}
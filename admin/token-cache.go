@@ -0,0 +1,111 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// A token is renewed when it's about to expire, so that it's still valid
+// when the request reaches the broker
+const tokenExpiryMargin = 30 * time.Second
+
+// cachedToken is a token obtained from an external source, with its
+// expiration time
+type cachedToken struct {
+	Token  string    `json:"token"`
+	Expiry time.Time `json:"expiry"`
+}
+
+func (t *cachedToken) valid() bool {
+	return t != nil && t.Token != "" && time.Now().Add(tokenExpiryMargin).Before(t.Expiry)
+}
+
+// tokenCache stores a token on disk, so that it's reused across invocations
+// until it expires. The empty cache does not store anything.
+type tokenCache string
+
+// newTokenCache returns the cache in dir for the tokens identified by the
+// given key parts, eg: the client id and the requested audience
+func newTokenCache(dir string, key ...string) tokenCache {
+	if dir == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(key, "\n")))
+	return tokenCache(filepath.Join(dir, hex.EncodeToString(sum[:16])+".json"))
+}
+
+// read returns the cached token, if any. A missing or corrupted cache is
+// equivalent to an empty one.
+func (c tokenCache) read() *cachedToken {
+	if c == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(string(c))
+	if err != nil {
+		return nil
+	}
+
+	token := &cachedToken{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil
+	}
+	return token
+}
+
+// write stores the token. Failing to do it only means that the next
+// invocation obtains a new token, so the errors are ignored.
+func (c tokenCache) write(token *cachedToken) {
+	if c == "" {
+		return
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return
+	}
+
+	// The tokens are credentials
+	path := string(c)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	// Write the new token aside, so that a concurrent invocation never reads
+	// a partial file
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// remove drops the cached token, eg: when the broker rejected it
+func (c tokenCache) remove() {
+	if c != "" {
+		os.Remove(string(c))
+	}
+}
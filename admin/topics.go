@@ -0,0 +1,66 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/merlimat/pulsar-ctl/names"
+)
+
+// Topics gives access to the topics of a namespace
+type Topics interface {
+	// List returns the names of the topics in a namespace
+	List(ctx context.Context, namespace *names.NamespaceName) ([]string, error)
+
+	// Permissions returns the actions allowed to each role on a topic,
+	// including the ones granted on its namespace
+	Permissions(ctx context.Context, topic *names.TopicName) (map[string][]string, error)
+
+	// GrantPermission allows the actions to a role on a topic, replacing the
+	// ones it was allowed before on the topic
+	GrantPermission(ctx context.Context, topic *names.TopicName, role string, actions []string) error
+
+	// RevokePermission removes the permissions of a role on a topic. The ones
+	// granted on its namespace still apply.
+	RevokePermission(ctx context.Context, topic *names.TopicName, role string) error
+}
+
+type topics struct {
+	client *Client
+}
+
+func (t *topics) List(ctx context.Context, namespace *names.NamespaceName) ([]string, error) {
+	// The v1 admin API still calls the topics destinations
+	if namespace.Cluster() != "" {
+		return t.client.RestGetStringList(ctx, namespace.AdminPath()+"/destinations")
+	}
+	return t.client.RestGetStringList(ctx, namespace.AdminPath()+"/topics")
+}
+
+func (t *topics) Permissions(ctx context.Context, topic *names.TopicName) (map[string][]string, error) {
+	permissions := map[string][]string{}
+	err := t.client.RestGet(ctx, topic.AdminPath()+"/permissions", &permissions)
+	return permissions, err
+}
+
+func (t *topics) GrantPermission(ctx context.Context, topic *names.TopicName, role string, actions []string) error {
+	return t.client.RestPost(ctx, topic.AdminPath()+"/permissions/"+url.PathEscape(role), actions)
+}
+
+func (t *topics) RevokePermission(ctx context.Context, topic *names.TopicName, role string) error {
+	return t.client.RestDelete(ctx, topic.AdminPath()+"/permissions/"+url.PathEscape(role))
+}
@@ -0,0 +1,154 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/resty.v1"
+)
+
+const redacted = "<redacted>"
+
+// traceRequest logs an outgoing request, according to the verbosity, and
+// the equivalent curl command if requested
+func (c *Client) traceRequest(r *resty.Request, method string, url string, content interface{}) {
+	if c.verbosity == 0 && !c.printCurl {
+		return
+	}
+
+	body, _ := encodeBody(content)
+
+	if c.printCurl {
+		fmt.Fprintln(c.traceOutput, c.curlCommand(method, url, r.Header, body))
+	}
+
+	if c.verbosity >= 1 {
+		fmt.Fprintf(c.traceOutput, "> %s %s\n", method, url)
+	}
+	if c.verbosity >= 2 {
+		c.traceHeaders(">", r.Header)
+	}
+	if c.verbosity >= 3 && len(body) > 0 {
+		fmt.Fprintf(c.traceOutput, "> %s\n", body)
+	}
+}
+
+// traceResponse logs the response status, or the failure, with the time it
+// took to receive it
+func (c *Client) traceResponse(resp *resty.Response, err error, elapsed time.Duration) {
+	if c.verbosity == 0 {
+		return
+	}
+
+	elapsed = elapsed.Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(c.traceOutput, "< request failed after %s: %s\n", elapsed, err)
+		return
+	}
+
+	fmt.Fprintf(c.traceOutput, "< %s in %s\n", resp.Status(), elapsed)
+	if c.verbosity >= 2 {
+		c.traceHeaders("<", resp.Header())
+	}
+	if c.verbosity >= 3 && len(resp.Body()) > 0 {
+		fmt.Fprintf(c.traceOutput, "< %s\n", resp.Body())
+	}
+}
+
+// traceRedirect is a redirect policy that only logs the redirect hops
+func (c *Client) traceRedirect(req *http.Request, via []*http.Request) error {
+	if c.verbosity >= 1 {
+		fmt.Fprintf(c.traceOutput, "< redirected to %s %s\n", req.Method, req.URL)
+	}
+	return nil
+}
+
+func (c *Client) traceHeaders(prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(c.traceOutput, "%s %s: %s\n", prefix, name, redactHeader(name, value))
+		}
+	}
+}
+
+func redactHeader(name string, value string) string {
+	if http.CanonicalHeaderKey(name) != "Authorization" {
+		return value
+	}
+
+	// Keep the scheme, to tell the authentication method
+	if i := strings.Index(value, " "); i > 0 {
+		return value[:i+1] + redacted
+	}
+	return redacted
+}
+
+// curlCommand returns a curl command line equivalent to the request. The
+// credentials are redacted, so the command can be safely shared.
+func (c *Client) curlCommand(method string, url string, header http.Header, body []byte) string {
+	args := []string{"curl", "-L", "-X", method}
+	args = append(args, c.curlTLSArgs...)
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			args = append(args, "-H", shellQuote(name+": "+redactHeader(name, value)))
+		}
+	}
+
+	if len(body) > 0 {
+		args = append(args, "-d", shellQuote(string(body)))
+	}
+
+	args = append(args, shellQuote(url))
+	return strings.Join(args, " ")
+}
+
+func curlTLSArgs(config *Config) []string {
+	args := []string{}
+	if config.TLSAllowInsecureConnection {
+		args = append(args, "-k")
+	}
+	if config.TLSTrustCertsFilePath != "" {
+		args = append(args, "--cacert", shellQuote(config.TLSTrustCertsFilePath))
+	}
+	if config.TLSCertFile != "" {
+		args = append(args, "--cert", shellQuote(config.TLSCertFile))
+	}
+	if config.TLSKeyFile != "" {
+		args = append(args, "--key", shellQuote(config.TLSKeyFile))
+	}
+	return args
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
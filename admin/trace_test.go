@@ -0,0 +1,102 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	var trace bytes.Buffer
+	client := newTestClient(t, &Config{AuthToken: "secret-token", Verbosity: 3, PrintCurl: true, TraceOutput: &trace},
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/admin/v2/tenants" {
+				http.Redirect(w, r, "/admin/v2/tenants/", http.StatusTemporaryRedirect)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+	if err := client.RestPut(context.Background(), "/admin/v2/tenants", map[string]string{"name": "it's"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	output := trace.String()
+	url := client.WebServiceUrl() + "/admin/v2/tenants"
+	for _, expected := range []string{
+		"curl -L -X PUT -H 'Accept: application/json' -H 'Authorization: Bearer <redacted>' " +
+			`-H 'Content-Type: application/json' -H 'User-Agent: pulsar-ctl' -d '{"name":"it'\''s"}' '` + url + "'\n",
+		"> PUT " + url + "\n",
+		"> Authorization: Bearer <redacted>\n",
+		"> User-Agent: pulsar-ctl\n",
+		`> {"name":"it's"}` + "\n",
+		"< redirected to PUT " + url + "/\n",
+		"< 204 No Content in ",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("missing %q in the trace:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "secret-token") {
+		t.Errorf("the token is not redacted in the trace:\n%s", output)
+	}
+}
+
+func TestTraceDisabled(t *testing.T) {
+	var trace bytes.Buffer
+	client := newTestClient(t, &Config{TraceOutput: &trace}, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	})
+
+	if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if trace.Len() > 0 {
+		t.Errorf("unexpected trace:\n%s", trace.String())
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	for _, test := range []struct {
+		name, value, expected string
+	}{
+		{"Authorization", "Bearer eyJhbGciOi", "Bearer <redacted>"},
+		{"authorization", "Basic dXNlcjpwYXNz", "Basic <redacted>"},
+		{"Authorization", "secret", "<redacted>"},
+		{"Accept", "application/json", "application/json"},
+	} {
+		if actual := redactHeader(test.name, test.value); actual != test.expected {
+			t.Errorf("redactHeader(%s, %s) = %s, expected %s", test.name, test.value, actual, test.expected)
+		}
+	}
+}
+
+func TestCurlTLSArgs(t *testing.T) {
+	args := curlTLSArgs(&Config{
+		TLSAllowInsecureConnection: true,
+		TLSTrustCertsFilePath:      "/etc/pulsar/ca.pem",
+		TLSCertFile:                "/etc/pulsar/client's.pem",
+		TLSKeyFile:                 "/etc/pulsar/client.key",
+	})
+
+	expected := `-k --cacert '/etc/pulsar/ca.pem' --cert '/etc/pulsar/client'\''s.pem' --key '/etc/pulsar/client.key'`
+	if actual := strings.Join(args, " "); actual != expected {
+		t.Errorf("got %s, expected %s", actual, expected)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// Policies to select the admin service URL for each request, when more than
+// one is configured
+const (
+	// Send the requests to the last URL that was reachable, moving to the
+	// next one only when it fails
+	URLSelectionFailover = "failover"
+
+	// Spread the requests across all the URLs, still moving to the next one
+	// when a URL fails
+	URLSelectionRoundRobin = "round-robin"
+)
+
+// serviceUrls is the list of admin service URLs of a client, along with the
+// state to pick the one to use for the next request
+type serviceUrls struct {
+	urls       []string
+	roundRobin bool
+
+	// Index of the last URL that was reachable
+	healthy int32
+
+	// Counter for the round-robin selection
+	next uint32
+}
+
+// parseServiceUrls accepts a comma separated list of URLs, either complete or
+// in the compact multi-host form used by Pulsar, eg:
+// http://host-1:8080,host-2:8080
+func parseServiceUrls(serviceUrl string, selection string) (*serviceUrls, error) {
+	s := &serviceUrls{}
+
+	switch selection {
+	case "", URLSelectionFailover:
+	case URLSelectionRoundRobin:
+		s.roundRobin = true
+	default:
+		return nil, fmt.Errorf("unknown URL selection policy '%s', expected '%s' or '%s'",
+			selection, URLSelectionFailover, URLSelectionRoundRobin)
+	}
+
+	scheme := ""
+	for _, part := range strings.Split(serviceUrl, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if !strings.Contains(part, "://") {
+			if scheme == "" {
+				return nil, fmt.Errorf("invalid admin service URL '%s': missing scheme", part)
+			}
+			part = scheme + "://" + part
+		}
+
+		u, err := url.Parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin service URL '%s': %s", part, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid admin service URL '%s': the scheme must be http or https", part)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid admin service URL '%s': missing host", part)
+		}
+
+		scheme = u.Scheme
+		s.urls = append(s.urls, strings.TrimSuffix(part, "/"))
+	}
+
+	if len(s.urls) == 0 {
+		return nil, fmt.Errorf("the admin service URL is not set")
+	}
+	return s, nil
+}
+
+// first returns the index of the URL to try first for a new request
+func (s *serviceUrls) first() int {
+	if s.roundRobin {
+		return int((atomic.AddUint32(&s.next, 1) - 1) % uint32(len(s.urls)))
+	}
+	return int(atomic.LoadInt32(&s.healthy))
+}
+
+func (s *serviceUrls) markHealthy(index int) {
+	atomic.StoreInt32(&s.healthy, int32(index))
+}
+
+// current returns the URL that the next request would use first
+func (s *serviceUrls) current() string {
+	return s.urls[atomic.LoadInt32(&s.healthy)]
+}
@@ -0,0 +1,112 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseServiceUrls(t *testing.T) {
+	for serviceUrl, expected := range map[string][]string{
+		"http://localhost:8080":                      {"http://localhost:8080"},
+		"http://localhost:8080/":                     {"http://localhost:8080"},
+		"http://host-1:8080,http://host-2:8080":      {"http://host-1:8080", "http://host-2:8080"},
+		"https://host-1:8443, host-2:8443,":          {"https://host-1:8443", "https://host-2:8443"},
+		"http://host-1:8080,https://host-2:8443,h-3": {"http://host-1:8080", "https://host-2:8443", "https://h-3"},
+	} {
+		s, err := parseServiceUrls(serviceUrl, "")
+		if err != nil {
+			t.Errorf("parseServiceUrls(%s): unexpected error: %s", serviceUrl, err)
+		} else if !reflect.DeepEqual(s.urls, expected) {
+			t.Errorf("parseServiceUrls(%s) = %v, expected %v", serviceUrl, s.urls, expected)
+		}
+	}
+
+	for _, serviceUrl := range []string{"", ",", "localhost:8080", "pulsar://localhost:6650", "http://"} {
+		if _, err := parseServiceUrls(serviceUrl, ""); err == nil {
+			t.Errorf("parseServiceUrls(%s): expected an error", serviceUrl)
+		}
+	}
+
+	if _, err := parseServiceUrls("http://localhost:8080", "random"); err == nil {
+		t.Errorf("expected an error for an unknown selection policy")
+	}
+}
+
+// countingServer starts a server answering with the given status and
+// counting the requests
+func countingServer(t *testing.T, status int, requests *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.WriteHeader(status)
+		w.Write([]byte("[]"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFailover(t *testing.T) {
+	var unavailable, healthy int32
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	serviceUrl := down.URL + "," +
+		countingServer(t, http.StatusServiceUnavailable, &unavailable).URL + "," +
+		countingServer(t, http.StatusOK, &healthy).URL
+	client, err := New(&Config{WebServiceUrl: serviceUrl})
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	// Only the first request goes through the unreachable and unavailable
+	// URLs, the next ones go straight to the healthy one
+	if unavailable != 1 || healthy != 3 {
+		t.Errorf("got %d requests to the unavailable URL and %d to the healthy one, expected 1 and 3",
+			unavailable, healthy)
+	}
+	if client.WebServiceUrl() != client.serviceUrls.urls[2] {
+		t.Errorf("the current URL is %s, expected %s", client.WebServiceUrl(), client.serviceUrls.urls[2])
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	var first, second int32
+	serviceUrl := countingServer(t, http.StatusOK, &first).URL + "," + countingServer(t, http.StatusOK, &second).URL
+	client, err := New(&Config{WebServiceUrl: serviceUrl, URLSelection: URLSelectionRoundRobin})
+	if err != nil {
+		t.Fatalf("New: %s", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if err := client.RestGet(context.Background(), "/admin/v2/clusters", nil); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if first != 2 || second != 2 {
+		t.Errorf("got %d and %d requests, expected 2 to each URL", first, second)
+	}
+}
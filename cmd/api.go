@@ -0,0 +1,116 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Methods accepted by the api command, the ones used by the admin REST API
+var apiMethods = []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete}
+
+func newApiCommand(c *cli) *cobra.Command {
+	var data string
+	var file string
+
+	var apiCmd = &cobra.Command{
+		Use:   "api <method> <path>",
+		Short: "Send a request to any endpoint of the admin REST API",
+		Long: `Send a request to any endpoint of the admin REST API
+
+The request uses the same admin URL, authentication and TLS settings as the
+other commands. A JSON response is printed in the format selected with
+--output, any other response as it is.`,
+		Example: `pulsar-ctl api GET /admin/v2/brokers/health
+pulsar-ctl api PUT /admin/v2/namespaces/my-tenant/my-namespace -d '{"bundles": {"numBundles": 8}}'
+pulsar-ctl api POST /admin/v2/namespaces/my-tenant/my-namespace/retention -f retention.json`,
+
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+				return err
+			}
+			if data != "" && file != "" {
+				return errors.New("only one of --data and --file can be given")
+			}
+
+			method := strings.ToUpper(args[0])
+			for _, allowed := range apiMethods {
+				if method == allowed {
+					return nil
+				}
+			}
+			return fmt.Errorf("invalid method '%s', expected one of %s", args[0], strings.Join(apiMethods, ", "))
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			method := strings.ToUpper(args[0])
+			path := args[1]
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+
+			body := []byte(data)
+			if file != "" {
+				var err error
+				if file == "-" {
+					body, err = ioutil.ReadAll(c.Stdin)
+				} else {
+					body, err = ioutil.ReadFile(file)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to read the request body: %s", err)
+				}
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			response, err := client.RestCall(c.ctx, method, path, body)
+			if err != nil || len(bytes.TrimSpace(response)) == 0 {
+				return err
+			}
+
+			// Keep the numbers as they are, eg: 64 bit ids
+			var obj interface{}
+			decoder := json.NewDecoder(bytes.NewReader(response))
+			decoder.UseNumber()
+			if decoder.Decode(&obj) != nil {
+				_, err = c.Stdout.Write(response)
+				if err == nil && !bytes.HasSuffix(response, []byte("\n")) {
+					_, err = fmt.Fprintln(c.Stdout)
+				}
+				return err
+			}
+			return c.printObject(&rawObject{data: obj}, "json")
+		},
+	}
+
+	apiCmd.Flags().StringVarP(&data, "data", "d", "",
+		"Body of the request, in JSON")
+	apiCmd.Flags().StringVarP(&file, "file", "f", "",
+		"File with the body of the request, in JSON, or - to read it from stdin")
+
+	return apiCmd
+}
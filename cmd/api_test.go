@@ -0,0 +1,39 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+func TestApi(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddCluster("standalone", admin.ClusterData{ServiceUrl: "http://standalone:8080"})
+
+	c.run("api", "GET", "/admin/v2/clusters")
+	c.run("api", "get", "admin/v2/clusters", "-o", "name")
+	c.run("api", "PUT", "/admin/v2/tenants/public", "-d", `{"adminRoles": ["admin"], "allowedClusters": ["standalone"]}`)
+	c.run("api", "PUT", "/admin/v2/tenants/sample", "-f", "testdata/tenant.json")
+	c.runWithInput(`{"allowedClusters": ["missing"]}`, "api", "POST", "/admin/v2/tenants/sample", "-f", "-")
+	c.run("api", "GET", "/admin/v2/tenants/public", "-o", "yaml")
+	c.run("api", "DELETE", "/admin/v2/tenants/sample", "--dry-run")
+	c.run("api", "DELETE", "/admin/v2/tenants/missing")
+	c.run("api", "PATCH", "/admin/v2/tenants/public")
+	c.run("api", "PUT", "/admin/v2/tenants/public", "-d", "{}", "-f", "testdata/tenant.json")
+	c.check()
+}
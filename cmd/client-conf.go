@@ -0,0 +1,353 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+// Keys of Pulsar's client.conf that are used by pulsar-ctl, besides the ones
+// that can also be set through flags
+const (
+	brokerServiceUrlKey = "brokerServiceUrl"
+	authPluginKey       = "authPlugin"
+	authParamsKey       = "authParams"
+)
+
+// Authentication plugins of the Pulsar Java client supported by pulsar-ctl
+const (
+	authenticationTokenPlugin  = "org.apache.pulsar.client.impl.auth.AuthenticationToken"
+	authenticationOAuth2Plugin = "org.apache.pulsar.client.impl.auth.oauth2.AuthenticationOAuth2"
+	authenticationBasicPlugin  = "org.apache.pulsar.client.impl.auth.AuthenticationBasic"
+	authenticationTlsPlugin    = "org.apache.pulsar.client.impl.auth.AuthenticationTls"
+)
+
+// loadClientConf merges the settings from the given client.conf into the
+// configuration. Without an explicit path, the one in $PULSAR_CLIENT_CONF is
+// used, like the Pulsar tools do, or else the client.conf of the local Pulsar
+// installation, if any.
+func (c *cli) loadClientConf(path string) error {
+	if path == "" {
+		path = os.Getenv("PULSAR_CLIENT_CONF")
+	}
+	if path == "" {
+		pulsarHome := os.Getenv("PULSAR_HOME")
+		if pulsarHome == "" {
+			return nil
+		}
+
+		path = filepath.Join(pulsarHome, "conf", "client.conf")
+		if _, err := os.Stat(path); err != nil {
+			return nil
+		}
+	}
+
+	conf, err := readClientConf(path)
+	if err != nil {
+		return err
+	}
+	return c.viper.MergeConfigMap(conf)
+}
+
+// readClientConf loads a Pulsar client.conf file, keeping only the keys that
+// have a value
+func readClientConf(path string) (map[string]interface{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	properties, err := readProperties(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+
+	conf := map[string]interface{}{}
+	for key, value := range properties {
+		if value != "" {
+			conf[key] = value
+		}
+	}
+
+	// The admin tool needs the HTTP endpoint. When only the binary protocol
+	// endpoint is configured, assume the brokers use the default ports.
+	if _, ok := conf[webServiceUrlKey]; !ok {
+		if brokerServiceUrl, ok := conf[brokerServiceUrlKey]; ok {
+			webServiceUrl, err := webServiceUrlFromBrokerServiceUrl(brokerServiceUrl.(string))
+			if err != nil {
+				return nil, err
+			}
+			conf[webServiceUrlKey] = webServiceUrl
+		}
+	}
+
+	return conf, nil
+}
+
+func webServiceUrlFromBrokerServiceUrl(brokerServiceUrl string) (string, error) {
+	u, err := url.Parse(brokerServiceUrl)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s '%s': %s", brokerServiceUrlKey, brokerServiceUrl, err)
+	}
+
+	switch u.Scheme {
+	case "pulsar":
+		return "http://" + u.Hostname() + ":8080", nil
+	case "pulsar+ssl":
+		return "https://" + u.Hostname() + ":8443", nil
+	default:
+		return "", fmt.Errorf("invalid %s '%s': unknown scheme", brokerServiceUrlKey, brokerServiceUrl)
+	}
+}
+
+// applyAuthPlugin configures the client authentication from the authPlugin
+// and authParams settings, in the format used by the Pulsar Java client
+func applyAuthPlugin(config *admin.Config, authPlugin string, authParams string) error {
+	switch authPlugin {
+	case "":
+		return nil
+
+	case authenticationTokenPlugin, "token":
+		// The token plugin accepts "token:<token>", "file:<path>" or a JSON
+		// map with the token
+		switch {
+		case strings.HasPrefix(authParams, "token:"):
+			config.AuthToken = strings.TrimPrefix(authParams, "token:")
+		case strings.HasPrefix(authParams, "file:"):
+			path := strings.TrimPrefix(authParams, "file:")
+			config.AuthTokenFile = strings.TrimPrefix(path, "//")
+		case strings.HasPrefix(strings.TrimSpace(authParams), "{"):
+			params := map[string]string{}
+			if err := json.Unmarshal([]byte(authParams), &params); err != nil {
+				return fmt.Errorf("invalid authParams for %s: %s", authPlugin, err)
+			}
+			config.AuthToken = params["token"]
+		default:
+			config.AuthToken = authParams
+		}
+		return nil
+
+	case authenticationOAuth2Plugin, "oauth2":
+		// The OAuth2 plugin takes a JSON map, with the private key given as
+		// a file:// or data: URL
+		params := map[string]string{}
+		if err := json.Unmarshal([]byte(authParams), &params); err != nil {
+			return fmt.Errorf("invalid authParams for %s: %s", authPlugin, err)
+		}
+		if authType := params["type"]; authType != "" && authType != "client_credentials" {
+			return fmt.Errorf("unsupported OAuth2 flow '%s', only client_credentials is supported", authType)
+		}
+		config.OAuth2 = oauth2Config(params["issuerUrl"], params["audience"], params["privateKey"], params["scope"])
+		return nil
+
+	case authenticationBasicPlugin, "basic":
+		// The basic plugin accepts "userId:<user>,password:<password>" or
+		// the same settings as a JSON map
+		params, err := pluginParams(authParams)
+		if err != nil {
+			return fmt.Errorf("invalid authParams for %s: %s", authPlugin, err)
+		}
+		if params["userId"] == "" {
+			return fmt.Errorf("invalid authParams for %s: the userId is missing", authPlugin)
+		}
+		config.BasicAuthUser = params["userId"]
+		config.BasicAuthPassword = params["password"]
+		return nil
+
+	case authenticationTlsPlugin, "tls":
+		// The TLS plugin accepts "tlsCertFile:<path>,tlsKeyFile:<path>" or
+		// the same settings as a JSON map. The certificate given with the
+		// flags takes precedence.
+		params, err := pluginParams(authParams)
+		if err != nil {
+			return fmt.Errorf("invalid authParams for %s: %s", authPlugin, err)
+		}
+		if params["tlsCertFile"] == "" || params["tlsKeyFile"] == "" {
+			return fmt.Errorf("invalid authParams for %s: both tlsCertFile and tlsKeyFile are required", authPlugin)
+		}
+		if config.TLSCertFile == "" && config.TLSKeyFile == "" {
+			config.TLSCertFile = params["tlsCertFile"]
+			config.TLSKeyFile = params["tlsKeyFile"]
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported authPlugin '%s'", authPlugin)
+	}
+}
+
+// pluginParams parses the parameters of an authentication plugin, given as a
+// JSON map or as a list of comma separated key:value pairs
+func pluginParams(authParams string) (map[string]string, error) {
+	params := map[string]string{}
+	if strings.HasPrefix(strings.TrimSpace(authParams), "{") {
+		err := json.Unmarshal([]byte(authParams), &params)
+		return params, err
+	}
+
+	for _, pair := range strings.Split(authParams, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("expected key:value, got '%s'", pair)
+		}
+		params[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return params, nil
+}
+
+// oauth2Config returns the OAuth2 client credentials configuration, with
+// the tokens cached in the pulsar-ctl directory
+func oauth2Config(issuerUrl string, audience string, privateKey string, scope string) *admin.OAuth2Config {
+	return &admin.OAuth2Config{
+		IssuerUrl:  issuerUrl,
+		Audience:   audience,
+		PrivateKey: privateKey,
+		Scope:      scope,
+		CacheDir:   credentialsCacheDir("oauth2"),
+	}
+}
+
+// readProperties parses the Java properties file format
+func readProperties(r io.Reader) (map[string]string, error) {
+	properties := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	logicalLine := ""
+	continuation := false
+
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+
+		if !continuation && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		// A line ending with an odd number of backslashes continues on the
+		// next line
+		trailing := len(line) - len(strings.TrimRight(line, "\\"))
+		continuation = trailing%2 == 1
+		if continuation {
+			logicalLine += line[:len(line)-1]
+			continue
+		}
+
+		logicalLine += line
+		key, value, err := splitProperty(logicalLine)
+		if err != nil {
+			return nil, err
+		}
+		properties[key] = value
+		logicalLine = ""
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if logicalLine != "" {
+		key, value, err := splitProperty(logicalLine)
+		if err != nil {
+			return nil, err
+		}
+		properties[key] = value
+	}
+
+	return properties, nil
+}
+
+// splitProperty separates the key from the value. The key is terminated by
+// the first unescaped '=', ':' or white space.
+func splitProperty(line string) (string, string, error) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+
+	key := line[:end]
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	key, err := unescapeProperty(key)
+	if err != nil {
+		return "", "", err
+	}
+	value, err := unescapeProperty(rest)
+	if err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("malformed \\uxxxx escape in '%s'", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uxxxx escape in '%s'", s)
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
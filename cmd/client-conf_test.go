@@ -0,0 +1,287 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+	"github.com/merlimat/pulsar-ctl/testing/fakeadmin"
+)
+
+func TestReadProperties(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    string
+		expected map[string]string
+	}{
+		{"separators", "a=1\nb:2\nc 3\nd = 4\ne\t:\t5\nf\n", map[string]string{
+			"a": "1", "b": "2", "c": "3", "d": "4", "e": "5", "f": ""}},
+		{"comments", "# comment\n! comment\n  # indented comment\na=1 # not a comment\n", map[string]string{
+			"a": "1 # not a comment"}},
+		{"continuation", "a=one, \\\n    two, \\\n    three\nb=\\\\\nc=d\\\n", map[string]string{
+			"a": "one, two, three", "b": "\\", "c": "d"}},
+		{"unicode escapes", "a=caf\\u00e9\nb=\\u0041\\tc\n", map[string]string{
+			"a": "café", "b": "A\tc"}},
+		{"escaped separators", "a\\=b=c\nd\\:e:f\ng\\ h i\n", map[string]string{
+			"a=b": "c", "d:e": "f", "g h": "i"}},
+		{"separator in value", "webServiceUrl=http://localhost:8080/\n", map[string]string{
+			"webServiceUrl": "http://localhost:8080/"}},
+	} {
+		actual, err := readProperties(strings.NewReader(test.input))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s: got %q, expected %q", test.name, actual, test.expected)
+		}
+	}
+
+	for _, input := range []string{"a=\\u12\n", "a=\\uzzzz\n"} {
+		if _, err := readProperties(strings.NewReader(input)); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestWebServiceUrlFromBrokerServiceUrl(t *testing.T) {
+	for brokerServiceUrl, expected := range map[string]string{
+		"pulsar://localhost:6650":              "http://localhost:8080",
+		"pulsar://broker.example.com":          "http://broker.example.com:8080",
+		"pulsar+ssl://broker.example.com:6651": "https://broker.example.com:8443",
+	} {
+		actual, err := webServiceUrlFromBrokerServiceUrl(brokerServiceUrl)
+		if err != nil || actual != expected {
+			t.Errorf("%s: got %s, %v, expected %s", brokerServiceUrl, actual, err, expected)
+		}
+	}
+
+	for _, brokerServiceUrl := range []string{"http://localhost:8080", "://bad"} {
+		if _, err := webServiceUrlFromBrokerServiceUrl(brokerServiceUrl); err == nil {
+			t.Errorf("%s: expected an error", brokerServiceUrl)
+		}
+	}
+}
+
+func TestApplyAuthPlugin(t *testing.T) {
+	for _, test := range []struct {
+		plugin   string
+		params   string
+		expected admin.Config
+	}{
+		{"", "token:ignored", admin.Config{}},
+		{authenticationTokenPlugin, "token:abc.def", admin.Config{AuthToken: "abc.def"}},
+		{"token", "file:///etc/pulsar/token", admin.Config{AuthTokenFile: "/etc/pulsar/token"}},
+		{"token", "file:token.txt", admin.Config{AuthTokenFile: "token.txt"}},
+		{"token", `{"token": "abc.def"}`, admin.Config{AuthToken: "abc.def"}},
+		{"token", "abc.def", admin.Config{AuthToken: "abc.def"}},
+		{authenticationBasicPlugin, `{"userId": "admin", "password": "secret"}`,
+			admin.Config{BasicAuthUser: "admin", BasicAuthPassword: "secret"}},
+		{"basic", "userId:admin,password:pass:word", admin.Config{BasicAuthUser: "admin", BasicAuthPassword: "pass:word"}},
+		{"basic", "userId:admin", admin.Config{BasicAuthUser: "admin"}},
+		{authenticationTlsPlugin, "tlsCertFile:/certs/client.pem,tlsKeyFile:/certs/client.key",
+			admin.Config{TLSCertFile: "/certs/client.pem", TLSKeyFile: "/certs/client.key"}},
+		{"tls", ` tlsCertFile : /certs/client.pem , tlsKeyFile : /certs/client.key `,
+			admin.Config{TLSCertFile: "/certs/client.pem", TLSKeyFile: "/certs/client.key"}},
+		{"tls", `{"tlsCertFile": "/certs/client.pem", "tlsKeyFile": "/certs/client.key"}`,
+			admin.Config{TLSCertFile: "/certs/client.pem", TLSKeyFile: "/certs/client.key"}},
+	} {
+		config := admin.Config{}
+		if err := applyAuthPlugin(&config, test.plugin, test.params); err != nil {
+			t.Errorf("%s %s: %s", test.plugin, test.params, err)
+		} else if !reflect.DeepEqual(config, test.expected) {
+			t.Errorf("%s %s: got %+v, expected %+v", test.plugin, test.params, config, test.expected)
+		}
+	}
+}
+
+func TestApplyAuthPluginOAuth2(t *testing.T) {
+	params := `{"type": "client_credentials", "issuerUrl": "https://auth.example.com",
+		"audience": "urn:pulsar", "privateKey": "file:///etc/pulsar/credentials.json", "scope": "admin"}`
+	for _, plugin := range []string{authenticationOAuth2Plugin, "oauth2"} {
+		config := admin.Config{}
+		if err := applyAuthPlugin(&config, plugin, params); err != nil {
+			t.Fatalf("%s: %s", plugin, err)
+		}
+
+		oauth2 := config.OAuth2
+		if oauth2 == nil || oauth2.IssuerUrl != "https://auth.example.com" || oauth2.Audience != "urn:pulsar" ||
+			oauth2.PrivateKey != "file:///etc/pulsar/credentials.json" || oauth2.Scope != "admin" {
+			t.Errorf("%s: unexpected OAuth2 configuration %+v", plugin, oauth2)
+		}
+	}
+}
+
+func TestApplyAuthPluginTlsFlags(t *testing.T) {
+	// The client certificate given with the flags takes precedence
+	config := admin.Config{TLSCertFile: "/flags/client.pem", TLSKeyFile: "/flags/client.key"}
+	if err := applyAuthPlugin(&config, "tls", "tlsCertFile:/conf/client.pem,tlsKeyFile:/conf/client.key"); err != nil {
+		t.Fatal(err)
+	}
+	if config.TLSCertFile != "/flags/client.pem" || config.TLSKeyFile != "/flags/client.key" {
+		t.Errorf("the flags are overridden: %+v", config)
+	}
+}
+
+func TestApplyAuthPluginErrors(t *testing.T) {
+	for _, test := range []struct {
+		plugin string
+		params string
+		err    string
+	}{
+		{"org.apache.pulsar.client.impl.auth.AuthenticationSasl", "{}", "unsupported authPlugin"},
+		{"kerberos", "", "unsupported authPlugin"},
+		{"token", `{"token": `, "invalid authParams"},
+		{"oauth2", "issuerUrl:https://auth.example.com", "invalid authParams"},
+		{"oauth2", `{"type": "authorization_code"}`, "unsupported OAuth2 flow 'authorization_code'"},
+		{"basic", `{"userId": }`, "invalid authParams"},
+		{"basic", "userId", "expected key:value, got 'userId'"},
+		{"basic", "password:secret", "the userId is missing"},
+		{"basic", "", "the userId is missing"},
+		{"tls", "tlsCertFile:/certs/client.pem", "both tlsCertFile and tlsKeyFile are required"},
+		{"tls", "tlsCertFile:/certs/client.pem,/certs/client.key", "expected key:value"},
+		{"tls", `{"tlsCertFile": 1}`, "invalid authParams"},
+	} {
+		config := admin.Config{}
+		err := applyAuthPlugin(&config, test.plugin, test.params)
+		if err == nil {
+			t.Errorf("%s %s: expected an error", test.plugin, test.params)
+		} else if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s %s: expected error %q, got %q", test.plugin, test.params, test.err, err)
+		}
+	}
+}
+
+func TestPluginParams(t *testing.T) {
+	for _, test := range []struct {
+		params   string
+		expected map[string]string
+	}{
+		{"", map[string]string{}},
+		{"a:1", map[string]string{"a": "1"}},
+		{"a:1,,b:2,", map[string]string{"a": "1", "b": "2"}},
+		{"url:http://host:8080", map[string]string{"url": "http://host:8080"}},
+		{"a:", map[string]string{"a": ""}},
+		{` {"a": "1"}`, map[string]string{"a": "1"}},
+	} {
+		actual, err := pluginParams(test.params)
+		if err != nil {
+			t.Errorf("%q: %s", test.params, err)
+		} else if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%q: got %q, expected %q", test.params, actual, test.expected)
+		}
+	}
+}
+
+// writeClientConf writes a client.conf with the given admin service URL and
+// returns its path
+func writeClientConf(t *testing.T, path string, webServiceUrl string) string {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := "webServiceUrl=" + webServiceUrl + "\ntlsAllowInsecureConnection=true\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// runWithClientConf runs a command with a client connected to a fake admin
+// service, whatever the configuration, and returns the admin service URL it
+// was configured with
+func runWithClientConf(t *testing.T, args ...string) (string, int, string) {
+	server := fakeadmin.NewServer()
+	defer server.Close()
+
+	client, err := admin.New(&admin.Config{WebServiceUrl: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stderr bytes.Buffer
+	root, c := newRootCommand(Options{Stdout: ioutil.Discard, Stderr: &stderr, Client: client,
+		ContextsPath: filepath.Join(t.TempDir(), "config")})
+	root.SetArgs(append(args, "clusters", "list"))
+	code := c.execute(context.Background(), root)
+	return c.viper.GetString(webServiceUrlKey), code, stderr.String()
+}
+
+func TestLoadClientConf(t *testing.T) {
+	dir := t.TempDir()
+	writeClientConf(t, filepath.Join(dir, "home", "conf", "client.conf"), "http://home:8080")
+	envConf := writeClientConf(t, filepath.Join(dir, "env.conf"), "http://env:8080")
+	explicitConf := writeClientConf(t, filepath.Join(dir, "explicit.conf"), "http://explicit:8080")
+
+	check := func(description string, expected string, args ...string) {
+		t.Helper()
+		url, code, stderr := runWithClientConf(t, args...)
+		if code != ExitOK {
+			t.Errorf("%s: exit code %d: %s", description, code, stderr)
+		} else if url != expected {
+			t.Errorf("%s: got %s, expected %s", description, url, expected)
+		}
+	}
+
+	check("no client.conf", "http://localhost:8080/")
+
+	t.Setenv("PULSAR_HOME", filepath.Join(dir, "missing"))
+	check("no client.conf in $PULSAR_HOME", "http://localhost:8080/")
+
+	t.Setenv("PULSAR_HOME", filepath.Join(dir, "home"))
+	check("$PULSAR_HOME/conf/client.conf", "http://home:8080")
+
+	t.Setenv("PULSAR_CLIENT_CONF", envConf)
+	check("$PULSAR_CLIENT_CONF over $PULSAR_HOME", "http://env:8080")
+
+	check("--config over $PULSAR_CLIENT_CONF", "http://explicit:8080", "--config", explicitConf)
+	check("flags over --config", "http://flag:8080", "--config", explicitConf, "--admin-url", "http://flag:8080")
+}
+
+func TestLoadClientConfErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.conf")
+	if err := ioutil.WriteFile(invalid, []byte("brokerServiceUrl=http://localhost:6650\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		description string
+		env         string
+		args        []string
+		err         string
+	}{
+		{"missing --config", "", []string{"--config", filepath.Join(dir, "missing.conf")}, "no such file or directory"},
+		{"unreadable --config", "", []string{"--config", dir}, "is a directory"},
+		{"invalid --config", "", []string{"--config", invalid}, "unknown scheme"},
+		{"missing $PULSAR_CLIENT_CONF", filepath.Join(dir, "missing.conf"), nil, "no such file or directory"},
+	} {
+		t.Setenv("PULSAR_CLIENT_CONF", test.env)
+		_, code, stderr := runWithClientConf(t, test.args...)
+		if code != ExitError {
+			t.Errorf("%s: got exit code %d, expected %d", test.description, code, ExitError)
+		}
+		if !strings.HasPrefix(stderr, "Error: failed to read config file: ") || !strings.Contains(stderr, test.err) {
+			t.Errorf("%s: unexpected error %q", test.description, stderr)
+		}
+	}
+}
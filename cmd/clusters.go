@@ -15,46 +15,45 @@
 package cmd
 
 import (
+	"context"
+
+	"github.com/merlimat/pulsar-ctl/admin"
 	"github.com/spf13/cobra"
-	"fmt"
 )
 
-type ClusterData struct {
-	ServiceUrl          string `json:"serviceUrl"`
-	ServiceUrlTls       string `json:"serviceUrlTls"`
-	BrokerServiceUrl    string `json:"brokerServiceUrl"`
-	BrokerServiceUrlTls string `json:"brokerServiceUrlTls"`
-
-	// For given Cluster1(us-west1, us-east1) and Cluster2(us-west2, us-east2)
-	// Peer: [us-west1 -> us-west2] and [us-east1 -> us-east2]
-	PeerClusterNames []string `json:"peerClusterNames"`
-}
-
-const(
-	clustersBasePath = "/admin/v2/clusters"
-)
+func newClustersCommand(c *cli) *cobra.Command {
+	clustersCmd := &cobra.Command{
+		Use:   "clusters",
+		Short: "Operations about Pulsar's clusters",
+		Long:  `Manage Clusters`,
+	}
 
-func clusterPath(name string) string {
-	return clustersBasePath + "/" + name
+	clustersCmd.AddCommand(clustersCreate(c))
+	clustersCmd.AddCommand(clustersUpdate(c))
+	clustersCmd.AddCommand(clustersList(c))
+	clustersCmd.AddCommand(clustersGet(c))
+	clustersCmd.AddCommand(clustersDelete(c))
+	clustersCmd.AddCommand(newFailureDomainsCommand(c))
+	return clustersCmd
 }
 
-var clustersCmd = &cobra.Command{
-	Use:   "clusters",
-	Short: "Operations about Pulsar's clusters",
-	Long:  `Manage Clusters`,
-}
+// GetClustersList returns the names of all the clusters, excluding the "global" pseudo-cluster
+func GetClustersList(ctx context.Context, client *admin.Client) ([]string, error) {
+	clusters, err := client.Clusters().List(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-func GetClustersList() []string {
 	filtered := []string{}
-	for _, cluster := range RestGetStringList(clustersBasePath) {
+	for _, cluster := range clusters {
 		if cluster != "global" {
 			filtered = append(filtered, cluster)
 		}
 	}
-	return filtered
+	return filtered, nil
 }
 
-func clustersList() {
+func clustersList(c *cli) *cobra.Command {
 	var listCmd = &cobra.Command{
 		Use:   "list",
 		Short: "List all the clusters",
@@ -62,33 +61,49 @@ func clustersList() {
 		Example: "pulsar-ctl clusters list",
 		Args:    cobra.ExactArgs(0),
 
-		Run: func(cmd *cobra.Command, args []string) {
-			for _, cluster := range GetClustersList() {
-				fmt.Println(cluster)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			clusters, err := GetClustersList(c.ctx, client)
+			if err != nil {
+				return err
 			}
+			return c.printNames(clusters)
 		},
 	}
 
-	clustersCmd.AddCommand(listCmd);
+	return listCmd
 }
 
-func clustersGet() {
+func clustersGet(c *cli) *cobra.Command {
 	var getCmd = &cobra.Command{
 		Use:     "get",
 		Short:   "Get information regarding a cluster",
 		Example: "pulsar-ctl clusters get us-west",
 		Args:    cobra.ExactArgs(1),
 
-		Run: func(cmd *cobra.Command, args []string) {
-			RestPrint(clusterPath(args[0]))
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			cluster, err := client.Clusters().Get(c.ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return c.printObject(&clusterObject{name: args[0], cluster: cluster}, "json")
 		},
 	}
 
-	clustersCmd.AddCommand(getCmd);
+	return getCmd
 }
 
-func clustersCreate() {
-	cluster := ClusterData{}
+func clustersCreate(c *cli) *cobra.Command {
+	cluster := admin.ClusterData{}
 
 	var createCmd = &cobra.Command{
 		Use:     "create",
@@ -96,8 +111,12 @@ func clustersCreate() {
 		Example: "pulsar-ctl clusters create us-west --service-url pulsar://host:6650",
 		Args:    cobra.ExactArgs(1),
 
-		Run: func(cmd *cobra.Command, args []string) {
-			RestPut(clusterPath(args[0]), cluster)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.Clusters().Create(c.ctx, args[0], cluster)
 		},
 	}
 
@@ -115,11 +134,11 @@ func clustersCreate() {
 		"Comma separated peer-cluster names")
 
 	createCmd.MarkFlagRequired("service-url")
-	clustersCmd.AddCommand(createCmd);
+	return createCmd
 }
 
-func clustersUpdate() {
-	cluster := ClusterData{}
+func clustersUpdate(c *cli) *cobra.Command {
+	cluster := admin.ClusterData{}
 
 	var updateCmd = &cobra.Command{
 		Use:     "update",
@@ -127,8 +146,12 @@ func clustersUpdate() {
 		Example: "pulsar-ctl clusters update us-west --service-url pulsar://host:6650",
 		Args:    cobra.ExactArgs(1),
 
-		Run: func(cmd *cobra.Command, args []string) {
-			RestPost(clusterPath(args[0]), cluster)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.Clusters().Update(c.ctx, args[0], cluster)
 		},
 	}
 
@@ -146,30 +169,26 @@ func clustersUpdate() {
 		"Comma separated peer-cluster names")
 
 	updateCmd.MarkFlagRequired("service-url")
-	clustersCmd.AddCommand(updateCmd);
+	return updateCmd
 }
 
-func clustersDelete() {
+func clustersDelete(c *cli) *cobra.Command {
 	var deleteCmd = &cobra.Command{
 		Use:     "delete",
-		Short:   "Delete an existing cluster",
+		Short:   "Delete one or more existing clusters",
 		Example: "pulsar-ctl clusters delete us-west",
-		Args:    cobra.ExactArgs(1),
+		Args:    cobra.MinimumNArgs(1),
 
-		Run: func(cmd *cobra.Command, args []string) {
-			RestDelete(clustersBasePath + "/" + args[0])
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return forEachName(c.ctx, args, func(name string) error {
+				return client.Clusters().Delete(c.ctx, name)
+			})
 		},
 	}
 
-	clustersCmd.AddCommand(deleteCmd);
-}
-
-func init() {
-	clustersCreate()
-	clustersUpdate()
-	clustersList()
-	clustersGet()
-	clustersDelete()
-
-	rootCmd.AddCommand(clustersCmd)
+	return deleteCmd
 }
@@ -0,0 +1,56 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+func TestClusters(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddCluster("standalone", admin.ClusterData{
+		ServiceUrl:       "http://standalone:8080",
+		BrokerServiceUrl: "pulsar://standalone:6650",
+	})
+
+	c.run("clusters", "list")
+	c.run("clusters", "create", "us-west", "--service-url", "pulsar://us-west:6650",
+		"--admin-service-url", "http://us-west:8080", "--peer-clusters", "us-east")
+	c.run("clusters", "create", "us-west", "--service-url", "pulsar://us-west:6650")
+	c.run("clusters", "get", "us-west")
+	c.run("clusters", "get", "us-west", "-o", "wide")
+	c.run("clusters", "update", "us-west", "--service-url", "pulsar://us-west-2:6650")
+	c.run("clusters", "get", "us-west", "-o", "yaml")
+	c.run("clusters", "update", "us-east", "--service-url", "pulsar://us-east:6650")
+	c.run("clusters", "list", "-o", "json")
+	c.run("clusters", "delete", "us-west", "missing", "standalone")
+	c.run("clusters", "get", "missing", "-o", "json")
+	c.run("clusters", "list")
+	c.run("clusters", "get")
+	c.check()
+}
+
+func TestClustersDryRun(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+
+	c.run("clusters", "create", "us-west", "--service-url", "pulsar://us-west:6650", "--dry-run")
+	c.run("clusters", "delete", "us-west", "--dry-run")
+	c.run("clusters", "list")
+	c.check()
+}
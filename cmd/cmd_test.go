@@ -0,0 +1,128 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/testing/fakeadmin"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestMain(m *testing.M) {
+	// Isolate the commands from the configuration of the user
+	for _, variable := range []string{"PULSAR_HOME", "PULSAR_CLIENT_CONF", "PULSARCTL_CONFIG", "PULSAR_AUTH_TOKEN",
+		"PULSAR_AUTH_BASIC_USER", "PULSAR_AUTH_BASIC_PASSWORD"} {
+		os.Unsetenv(variable)
+	}
+	for _, variable := range os.Environ() {
+		if strings.HasPrefix(variable, "PULSAR_CTL_") {
+			os.Unsetenv(strings.SplitN(variable, "=", 2)[0])
+		}
+	}
+	os.Exit(m.Run())
+}
+
+// commandTest runs pulsar-ctl commands against a fake admin service and
+// records a transcript of their output, to compare with a golden file
+type commandTest struct {
+	t            *testing.T
+	server       *fakeadmin.Server
+	contextsPath string
+	transcript   bytes.Buffer
+}
+
+func newCommandTest(t *testing.T) *commandTest {
+	server := fakeadmin.NewServer()
+	t.Cleanup(server.Close)
+
+	contextsPath := filepath.Join(t.TempDir(), "config")
+	return &commandTest{t: t, server: server, contextsPath: contextsPath}
+}
+
+// run runs pulsar-ctl with the given arguments, connected to the fake admin
+// service, and adds the command line, its output and its exit code to the
+// transcript
+func (c *commandTest) run(args ...string) {
+	c.runWithInput("", args...)
+}
+
+// runWithInput is like run, with the given input on stdin
+func (c *commandTest) runWithInput(input string, args ...string) {
+	c.execute(context.Background(), input, []string{"--admin-url", c.server.URL}, args)
+}
+
+// runWithContext is like run, stopping the command when the context is
+// cancelled, like an interrupt does
+func (c *commandTest) runWithContext(ctx context.Context, args ...string) {
+	c.execute(ctx, "", []string{"--admin-url", c.server.URL}, args)
+}
+
+// runWithConfig is like run, without the address of the fake admin service,
+// which is taken from the context or client.conf instead
+func (c *commandTest) runWithConfig(args ...string) {
+	c.execute(context.Background(), "", nil, args)
+}
+
+// execute runs pulsar-ctl with the hidden arguments followed by the ones
+// recorded in the transcript
+func (c *commandTest) execute(ctx context.Context, input string, hidden []string, args []string) {
+	var stdout, stderr bytes.Buffer
+	root, cli := newRootCommand(Options{Stdin: strings.NewReader(input), Stdout: &stdout, Stderr: &stderr,
+		ContextsPath: c.contextsPath})
+	root.SetArgs(append(hidden, args...))
+	code := cli.execute(ctx, root)
+
+	fmt.Fprintf(&c.transcript, "$ pulsar-ctl %s\n", strings.Join(args, " "))
+	c.transcript.Write(stdout.Bytes())
+	c.transcript.Write(stderr.Bytes())
+	if code != ExitOK {
+		fmt.Fprintf(&c.transcript, "[exit code %d]\n", code)
+	}
+}
+
+// check compares the transcript with testdata/<test name>.golden, or updates
+// the golden file with -update
+func (c *commandTest) check() {
+	c.t.Helper()
+
+	// The address of the fake admin service changes at every run
+	actual := strings.Replace(c.transcript.String(), c.server.URL, "http://fakeadmin", -1)
+
+	golden := filepath.Join("testdata", c.t.Name()+".golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(actual), 0644); err != nil {
+			c.t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		c.t.Fatalf("%s (run the tests with -update to create it)", err)
+	}
+	if actual != string(expected) {
+		c.t.Errorf("output differs from %s:\n--- expected\n%s\n--- actual\n%s", golden, expected, actual)
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+func newConfigCommand(c *cli) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the contexts to connect to Pulsar clusters",
+		Long: `Manage the contexts to connect to Pulsar clusters
+
+A context is a named set of connection settings: admin URL, authentication,
+TLS settings and default tenant and namespace. The contexts are stored in
+~/.pulsar-ctl/config, or in the file pointed by $PULSARCTL_CONFIG.
+
+For example, creating a context and using it:
+
+    pulsar-ctl config set-context prod --admin-url https://pulsar.example.com:8443 \
+        --auth-token-file /etc/pulsar/token
+    pulsar-ctl config use-context prod
+
+Running a single command against another context:
+
+    pulsar-ctl --context staging tenants list
+`,
+
+		// The contexts are managed here, so the current one is not required to be valid
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			c.started = true
+		},
+	}
+
+	configCmd.AddCommand(configGetContexts(c))
+	configCmd.AddCommand(configCurrentContext(c))
+	configCmd.AddCommand(configUseContext(c))
+	configCmd.AddCommand(configSetContext(c))
+	configCmd.AddCommand(configDeleteContext(c))
+	configCmd.AddCommand(configView(c))
+	return configCmd
+}
+
+func configGetContexts(c *cli) *cobra.Command {
+	var getContextsCmd = &cobra.Command{
+		Use:     "get-contexts",
+		Short:   "List all the contexts",
+		Example: "pulsar-ctl config get-contexts",
+		Args:    cobra.ExactArgs(0),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := c.readContexts()
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(contexts.Contexts))
+			for name := range contexts.Contexts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				if name == contexts.CurrentContext {
+					fmt.Fprintln(c.Stdout, "*", name)
+				} else {
+					fmt.Fprintln(c.Stdout, " ", name)
+				}
+			}
+			return nil
+		},
+	}
+
+	return getContextsCmd
+}
+
+func configCurrentContext(c *cli) *cobra.Command {
+	var currentContextCmd = &cobra.Command{
+		Use:     "current-context",
+		Short:   "Display the current context",
+		Example: "pulsar-ctl config current-context",
+		Args:    cobra.ExactArgs(0),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := c.readContexts()
+			if err != nil {
+				return err
+			}
+
+			if contexts.CurrentContext == "" {
+				return errors.New("the current context is not set")
+			}
+			fmt.Fprintln(c.Stdout, contexts.CurrentContext)
+			return nil
+		},
+	}
+
+	return currentContextCmd
+}
+
+func configUseContext(c *cli) *cobra.Command {
+	var useContextCmd = &cobra.Command{
+		Use:     "use-context",
+		Short:   "Set the current context",
+		Example: "pulsar-ctl config use-context prod",
+		Args:    cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := c.readContexts()
+			if err != nil {
+				return err
+			}
+
+			if _, ok := contexts.Contexts[args[0]]; !ok {
+				return fmt.Errorf("context '%s' does not exist", args[0])
+			}
+
+			contexts.CurrentContext = args[0]
+			return c.writeContexts(contexts)
+		},
+	}
+
+	return useContextCmd
+}
+
+func configSetContext(c *cli) *cobra.Command {
+	var setContextCmd = &cobra.Command{
+		Use:   "set-context",
+		Short: "Create a context or update an existing one",
+		Long: `Create a context or update an existing one
+
+The connection settings are taken from the global flags, eg: --admin-url,
+--auth-token-file or --tls-trust-cert-path. Only the flags that are passed
+are modified in an existing context.`,
+		Example: "pulsar-ctl config set-context prod --admin-url https://pulsar.example.com:8443 --tenant my-tenant",
+		Args:    cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := c.readContexts()
+			if err != nil {
+				return err
+			}
+
+			context, ok := contexts.Contexts[args[0]]
+			if !ok {
+				context = &Context{}
+				contexts.Contexts[args[0]] = context
+			}
+
+			flags := cmd.Flags()
+			setString := func(flag string, value *string) {
+				if flags.Changed(flag) {
+					*value, _ = flags.GetString(flag)
+				}
+			}
+			setBool := func(flag string, value **bool) {
+				if flags.Changed(flag) {
+					b, _ := flags.GetBool(flag)
+					*value = &b
+				}
+			}
+
+			if flags.Changed("admin-url") {
+				adminUrl, _ := flags.GetString("admin-url")
+				context.AdminUrl = splitUrls(adminUrl)
+			}
+			setString("url-selection", &context.URLSelection)
+			setString("auth-token", &context.AuthToken)
+			setString("auth-token-file", &context.AuthTokenFile)
+			setString("oauth2-issuer-url", &context.OAuth2IssuerUrl)
+			setString("oauth2-audience", &context.OAuth2Audience)
+			setString("oauth2-private-key", &context.OAuth2PrivateKey)
+			setString("oauth2-scope", &context.OAuth2Scope)
+			setString("auth-basic-user", &context.BasicAuthUser)
+			setString("auth-basic-password", &context.BasicAuthPassword)
+			setString("auth-basic-password-file", &context.BasicAuthPasswordFile)
+			if flags.Changed("auth-exec-command") {
+				command, _ := flags.GetString("auth-exec-command")
+				context.Exec = nil
+				if command != "" {
+					context.Exec = &Exec{Command: command}
+				}
+			}
+			if flags.Changed("auth-exec-arg") && context.Exec != nil {
+				context.Exec.Args, _ = flags.GetStringArray("auth-exec-arg")
+			}
+			setString("tls-trust-cert-path", &context.TLSTrustCertPath)
+			setString("tls-cert-file", &context.TLSCertFile)
+			setString("tls-key-file", &context.TLSKeyFile)
+			setBool("tls-allow-insecure", &context.TLSAllowInsecure)
+			setBool("tls-enable-hostname-verification", &context.TLSEnableHostnameVerification)
+			setString("tenant", &context.Tenant)
+			setString("namespace", &context.Namespace)
+
+			return c.writeContexts(contexts)
+		},
+	}
+
+	setContextCmd.Flags().String("tenant", "",
+		"Default tenant for the commands run in this context")
+	setContextCmd.Flags().String("namespace", "",
+		"Default namespace, within the default tenant, for the commands run in this context")
+
+	return setContextCmd
+}
+
+func configDeleteContext(c *cli) *cobra.Command {
+	var deleteContextCmd = &cobra.Command{
+		Use:     "delete-context",
+		Short:   "Delete a context",
+		Example: "pulsar-ctl config delete-context staging",
+		Args:    cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := c.readContexts()
+			if err != nil {
+				return err
+			}
+
+			if _, ok := contexts.Contexts[args[0]]; !ok {
+				return fmt.Errorf("context '%s' does not exist", args[0])
+			}
+
+			delete(contexts.Contexts, args[0])
+			if contexts.CurrentContext == args[0] {
+				contexts.CurrentContext = ""
+			}
+			return c.writeContexts(contexts)
+		},
+	}
+
+	return deleteContextCmd
+}
+
+func configView(c *cli) *cobra.Command {
+	var raw bool
+
+	var viewCmd = &cobra.Command{
+		Use:     "view",
+		Short:   "Display the contexts",
+		Example: "pulsar-ctl config view",
+		Args:    cobra.ExactArgs(0),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := c.readContexts()
+			if err != nil {
+				return err
+			}
+
+			if !raw {
+				for _, context := range contexts.Contexts {
+					if context.AuthToken != "" {
+						context.AuthToken = "REDACTED"
+					}
+					if context.BasicAuthPassword != "" {
+						context.BasicAuthPassword = "REDACTED"
+					}
+					// A path to the credentials file is not a secret, but
+					// the credentials given inline are
+					if strings.HasPrefix(context.OAuth2PrivateKey, "data:") {
+						context.OAuth2PrivateKey = "REDACTED"
+					}
+				}
+			}
+
+			data, err := yaml.Marshal(contexts)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(c.Stdout, string(data))
+			return nil
+		},
+	}
+
+	viewCmd.Flags().BoolVar(&raw, "raw", false, "Display the credentials instead of redacting them")
+
+	return viewCmd
+}
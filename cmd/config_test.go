@@ -0,0 +1,43 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+
+	c.run("config", "get-contexts")
+	c.run("config", "set-context", "prod", "--url-selection", "round-robin", "--auth-token", "secret",
+		"--tenant", "my-tenant")
+	c.run("config", "set-context", "staging", "--auth-exec-command", "get-token", "--auth-exec-arg", "staging")
+	c.run("config", "set-context", "oauth2", "--oauth2-issuer-url", "https://auth.example.com",
+		"--oauth2-private-key", "data:application/json;base64,eyJjbGllbnRfc2VjcmV0IjoiczNjcjN0In0=")
+	c.run("config", "set-context", "oauth2-file", "--oauth2-private-key", "/etc/pulsar/credentials.json")
+	c.run("config", "current-context")
+	c.run("config", "use-context", "prod")
+	c.run("config", "use-context", "missing")
+	c.run("config", "get-contexts")
+	c.run("config", "current-context")
+	c.run("config", "view")
+	c.run("config", "view", "--raw")
+	c.run("config", "delete-context", "prod")
+	c.run("config", "delete-context", "prod")
+	c.run("config", "view")
+	c.check()
+}
@@ -0,0 +1,299 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/merlimat/pulsar-ctl/names"
+	homedir "github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v2"
+)
+
+// Configuration keys for the defaults taken from the context
+const (
+	defaultTenantKey    = "defaultTenant"
+	defaultNamespaceKey = "defaultNamespace"
+)
+
+// Contexts holds the named connection settings for the Pulsar clusters
+// managed with pulsar-ctl, in the style of kubectl's kubeconfig
+type Contexts struct {
+	CurrentContext string              `yaml:"current-context"`
+	Contexts       map[string]*Context `yaml:"contexts"`
+}
+
+// Context is a set of settings to connect to a Pulsar cluster. Empty fields
+// are not applied, leaving the value from client.conf or the default.
+type Context struct {
+	AdminUrl                      urlList `yaml:"admin-url,omitempty"`
+	URLSelection                  string  `yaml:"url-selection,omitempty"`
+	AuthToken                     string  `yaml:"auth-token,omitempty"`
+	AuthTokenFile                 string  `yaml:"auth-token-file,omitempty"`
+	OAuth2IssuerUrl               string  `yaml:"oauth2-issuer-url,omitempty"`
+	OAuth2Audience                string  `yaml:"oauth2-audience,omitempty"`
+	OAuth2PrivateKey              string  `yaml:"oauth2-private-key,omitempty"`
+	OAuth2Scope                   string  `yaml:"oauth2-scope,omitempty"`
+	Exec                          *Exec   `yaml:"exec,omitempty"`
+	BasicAuthUser                 string  `yaml:"basic-auth-user,omitempty"`
+	BasicAuthPassword             string  `yaml:"basic-auth-password,omitempty"`
+	BasicAuthPasswordFile         string  `yaml:"basic-auth-password-file,omitempty"`
+	TLSTrustCertPath              string  `yaml:"tls-trust-cert-path,omitempty"`
+	TLSCertFile                   string  `yaml:"tls-cert-file,omitempty"`
+	TLSKeyFile                    string  `yaml:"tls-key-file,omitempty"`
+	TLSAllowInsecure              *bool   `yaml:"tls-allow-insecure,omitempty"`
+	TLSEnableHostnameVerification *bool   `yaml:"tls-enable-hostname-verification,omitempty"`
+
+	// Defaults for the commands that take a tenant or a namespace
+	Tenant    string `yaml:"tenant,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// Exec is a credential plugin: a command that prints the token to use, as
+// JSON with the token and its expiry
+type Exec struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args,omitempty"`
+}
+
+// urlList holds the admin service URLs of a context. In the file, it is
+// either a single URL or a list of URLs.
+type urlList []string
+
+func (l *urlList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*l = splitUrls(single)
+		return nil
+	}
+
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+func (l urlList) MarshalYAML() (interface{}, error) {
+	if len(l) == 1 {
+		return l[0], nil
+	}
+	return []string(l), nil
+}
+
+func splitUrls(urls string) urlList {
+	list := urlList{}
+	for _, url := range strings.Split(urls, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			list = append(list, url)
+		}
+	}
+	return list
+}
+
+// contextsPath returns the location of the contexts file, which can be
+// changed with the options or $PULSARCTL_CONFIG
+func (c *cli) contextsPath() (string, error) {
+	if c.ContextsPath != "" {
+		return c.ContextsPath, nil
+	}
+	if path := os.Getenv("PULSARCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".pulsar-ctl", "config"), nil
+}
+
+// credentialsCacheDir returns the directory where the tokens obtained by the
+// named authentication method are cached, or "" to not cache them when the
+// home directory is unknown
+func credentialsCacheDir(name string) string {
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".pulsar-ctl", "cache", name)
+}
+
+// readContexts loads the contexts file. A missing file is equivalent to an
+// empty one.
+func (c *cli) readContexts() (*Contexts, error) {
+	contexts := &Contexts{Contexts: map[string]*Context{}}
+
+	path, err := c.contextsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return contexts, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, contexts); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %s", path, err)
+	}
+	if contexts.Contexts == nil {
+		contexts.Contexts = map[string]*Context{}
+	}
+	return contexts, nil
+}
+
+func (c *cli) writeContexts(contexts *Contexts) error {
+	path, err := c.contextsPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(contexts)
+	if err != nil {
+		return err
+	}
+
+	// The file can contain credentials
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// loadContext merges the settings of the named context, or of the current
+// one, into the configuration. They take precedence over client.conf.
+func (c *cli) loadContext(name string) error {
+	contexts, err := c.readContexts()
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = contexts.CurrentContext
+		if name == "" {
+			return nil
+		}
+	}
+
+	context, ok := contexts.Contexts[name]
+	if !ok {
+		return fmt.Errorf("context '%s' does not exist", name)
+	}
+
+	c.context = name
+	return c.viper.MergeConfigMap(context.settings())
+}
+
+// settings returns the non-empty fields of the context, keyed like in client.conf
+func (c *Context) settings() map[string]interface{} {
+	settings := map[string]interface{}{}
+
+	set := func(key string, value string) {
+		if value != "" {
+			settings[key] = value
+		}
+	}
+
+	set(webServiceUrlKey, strings.Join(c.AdminUrl, ","))
+	set(urlSelectionKey, c.URLSelection)
+	set(authTokenKey, c.AuthToken)
+	set(authTokenFileKey, c.AuthTokenFile)
+	set(oauth2IssuerUrlKey, c.OAuth2IssuerUrl)
+	set(oauth2AudienceKey, c.OAuth2Audience)
+	set(oauth2PrivateKeyKey, c.OAuth2PrivateKey)
+	set(oauth2ScopeKey, c.OAuth2Scope)
+	set(basicAuthUserKey, c.BasicAuthUser)
+	set(basicAuthPasswordKey, c.BasicAuthPassword)
+	set(basicAuthPasswordFileKey, c.BasicAuthPasswordFile)
+	set(tlsTrustCertsFilePathKey, c.TLSTrustCertPath)
+	set(tlsCertificateFilePathKey, c.TLSCertFile)
+	set(tlsKeyFilePathKey, c.TLSKeyFile)
+	set(defaultTenantKey, c.Tenant)
+	set(defaultNamespaceKey, c.Namespace)
+
+	if c.Exec != nil {
+		settings[authExecCommandKey] = c.Exec.Command
+		settings[authExecArgsKey] = c.Exec.Args
+	}
+	if c.TLSAllowInsecure != nil {
+		settings[tlsAllowInsecureConnectionKey] = *c.TLSAllowInsecure
+	}
+	if c.TLSEnableHostnameVerification != nil {
+		settings[tlsEnableHostnameVerificationKey] = *c.TLSEnableHostnameVerification
+	}
+
+	// The credentials of the context replace the ones configured in
+	// client.conf
+	if c.AuthToken != "" || c.AuthTokenFile != "" || c.OAuth2PrivateKey != "" || c.Exec != nil || c.BasicAuthUser != "" {
+		for _, key := range []string{authTokenKey, authTokenFileKey, oauth2PrivateKeyKey, authExecCommandKey,
+			basicAuthUserKey, authPluginKey, authParamsKey} {
+			if _, ok := settings[key]; !ok {
+				settings[key] = ""
+			}
+		}
+	}
+	return settings
+}
+
+// defaultNamespace returns the namespace to use when it's not given on the
+// command line, in the form <tenant>/<namespace>
+func (c *cli) defaultNamespace() (string, error) {
+	tenant := c.viper.GetString(defaultTenantKey)
+	namespace := c.viper.GetString(defaultNamespaceKey)
+	if tenant == "" || namespace == "" {
+		return "", newUsageError(errors.New("the namespace was not specified and the context does not have a default tenant and namespace"))
+	}
+	return tenant + "/" + namespace, nil
+}
+
+// tenantArg returns the tenant passed as first argument, or the default one
+// from the context
+func (c *cli) tenantArg(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	tenant := c.viper.GetString(defaultTenantKey)
+	if tenant == "" {
+		return "", newUsageError(errors.New("the tenant was not specified and the context does not have a default tenant"))
+	}
+	return tenant, nil
+}
+
+// namespaceArg returns the namespace passed as first argument, or the default
+// one from the context
+func (c *cli) namespaceArg(args []string) (*names.NamespaceName, error) {
+	namespace := ""
+	if len(args) > 0 {
+		namespace = args[0]
+	} else {
+		var err error
+		if namespace, err = c.defaultNamespace(); err != nil {
+			return nil, err
+		}
+	}
+
+	name, err := names.NamespaceNameParse(namespace)
+	return name, newUsageError(err)
+}
@@ -0,0 +1,65 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+func TestContexts(t *testing.T) {
+	c := newCommandTest(t)
+	c.server.AddTenant("public", admin.TenantInfo{})
+	c.server.AddTenant("sample", admin.TenantInfo{})
+	c.server.AddNamespace("public/default")
+	c.server.AddNamespace("sample/orders")
+
+	// The client.conf points to a broker that is not running, with an
+	// authentication plugin that is not supported
+	clientConf := filepath.Join(t.TempDir(), "client.conf")
+	data := "webServiceUrl=http://127.0.0.1:1/\nauthPlugin=org.example.AuthenticationCustom\n"
+	if err := ioutil.WriteFile(clientConf, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PULSAR_CLIENT_CONF", clientConf)
+
+	c.run("config", "set-context", "public", "--admin-url", c.server.URL, "--auth-token", "secret",
+		"--tenant", "public", "--namespace", "default")
+	c.run("config", "set-context", "sample", "--admin-url", c.server.URL, "--auth-token", "secret",
+		"--tenant", "sample")
+	c.run("config", "set-context", "unreachable", "--admin-url", "http://127.0.0.1:1/", "--auth-token", "secret")
+
+	// Without context, client.conf applies
+	c.runWithConfig("namespaces", "list", "public")
+
+	// The context takes precedence over client.conf
+	c.run("config", "use-context", "public")
+	c.runWithConfig("namespaces", "list")
+	c.runWithConfig("namespaces", "get-retention")
+
+	// --context selects another context than the current one
+	c.runWithConfig("--context", "sample", "namespaces", "list")
+	c.runWithConfig("--context", "sample", "namespaces", "get-retention")
+	c.runWithConfig("--context", "missing", "namespaces", "list")
+	c.run("config", "current-context")
+
+	// The flags and the arguments take precedence over the context
+	c.runWithConfig("--context", "sample", "namespaces", "list", "public")
+	c.runWithConfig("--context", "unreachable", "--admin-url", c.server.URL, "namespaces", "list", "sample")
+	c.check()
+}
@@ -0,0 +1,180 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+// Exit codes of pulsar-ctl, so that scripts can tell the failures apart
+const (
+	// The command completed successfully
+	ExitOK = 0
+
+	// Any failure not covered by a more specific code
+	ExitError = 1
+
+	// Invalid command line: unknown command or flag, wrong arguments
+	ExitUsage = 2
+
+	// The resource does not exist (HTTP 404)
+	ExitNotFound = 3
+
+	// The credentials are missing, invalid or not allowed to perform the
+	// operation (HTTP 401 and 403), or they could not be obtained
+	// from the authorization server
+	ExitUnauthorized = 4
+
+	// The operation conflicts with the current state, eg: the resource
+	// already exists or is not empty (HTTP 409 and 412)
+	ExitConflict = 5
+
+	// The admin service could not be reached or is temporarily unavailable
+	// (connection errors, HTTP 429 and 503)
+	ExitUnavailable = 6
+
+	// The broker failed to process the request (other HTTP 5xx)
+	ExitServerError = 7
+
+	// The request or the command did not complete within the timeout
+	ExitTimeout = 8
+
+	// The command was interrupted with SIGINT or SIGTERM
+	ExitInterrupted = 130
+)
+
+const exitCodesHelp = `Exit codes:
+    0  Success
+    1  Generic error
+    2  Invalid command line
+    3  Resource not found
+    4  Unauthorized or forbidden
+    5  Conflict with the current state, eg: already exists
+    6  Admin service unreachable or unavailable
+    7  Server error
+    8  Timeout
+  130  Interrupted`
+
+// usageError is an invalid argument or flag value found by a command, after
+// the command line was parsed. It's reported like the parsing errors.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// newUsageError marks err as a usage error, keeping nil as it is
+func newUsageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &usageError{err: err}
+}
+
+// exitCode returns the exit code corresponding to a command failure
+func (c *cli) exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var usage *usageError
+	if !c.started || errors.As(err, &usage) {
+		return ExitUsage
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return ExitInterrupted
+	}
+
+	var timeout interface{ Timeout() bool }
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &timeout) && timeout.Timeout()) {
+		return ExitTimeout
+	}
+
+	var authError *admin.AuthError
+	if errors.As(err, &authError) {
+		return ExitUnauthorized
+	}
+
+	var adminError *admin.Error
+	if !errors.As(err, &adminError) {
+		return ExitError
+	}
+
+	switch status := adminError.StatusCode; {
+	case status == 0:
+		return ExitUnavailable
+	case status == http.StatusNotFound:
+		return ExitNotFound
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ExitUnauthorized
+	case status == http.StatusConflict, status == http.StatusPreconditionFailed:
+		return ExitConflict
+	case status == http.StatusTooManyRequests, status == http.StatusServiceUnavailable:
+		return ExitUnavailable
+	case status >= 500:
+		return ExitServerError
+	default:
+		return ExitError
+	}
+}
+
+// errorEnvelope is the error printed on stderr with --output json
+type errorEnvelope struct {
+	Error errorDetails `json:"error"`
+}
+
+type errorDetails struct {
+	Message    string `json:"message"`
+	ExitCode   int    `json:"exitCode"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	Method     string `json:"method,omitempty"`
+	Path       string `json:"path,omitempty"`
+}
+
+// printError reports a command failure on stderr, as JSON if that's the
+// selected output format
+func (c *cli) printError(err error, code int) {
+	if c.outputFormat != "json" {
+		fmt.Fprintln(c.Stderr, "Error:", err)
+		return
+	}
+
+	details := errorDetails{Message: err.Error(), ExitCode: code}
+
+	var adminError *admin.Error
+	if errors.As(err, &adminError) {
+		details.StatusCode = adminError.StatusCode
+		details.Reason = adminError.Reason
+		details.Method = adminError.Method
+		details.Path = adminError.Path
+	}
+
+	data, _ := json.MarshalIndent(errorEnvelope{Error: details}, "", "   ")
+	fmt.Fprintln(c.Stderr, string(data))
+}
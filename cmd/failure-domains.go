@@ -1,28 +1,28 @@
 package cmd
 
 import (
+	"github.com/merlimat/pulsar-ctl/admin"
 	"github.com/spf13/cobra"
-	"encoding/json"
-	"fmt"
 )
 
-func clusterFailureDomainsPath(cluster string, domain string) string {
-	return fmt.Sprintf("%s/%s/failureDomains/%s", clustersBasePath, cluster, domain)
-}
-
-type FailureDomain struct {
-	Brokers []string `json:"brokers"`
-}
-
-var failureDomainsCmd = &cobra.Command{
-	Use:   "failure-domains",
-	Short: "Manage failure domains for clusters",
-	Example: `pulsar-ctl clusters failure-domains create us-west my-domain
+func newFailureDomainsCommand(c *cli) *cobra.Command {
+	failureDomainsCmd := &cobra.Command{
+		Use:   "failure-domains",
+		Short: "Manage failure domains for clusters",
+		Example: `pulsar-ctl clusters failure-domains create us-west my-domain
 		--brokers pulsar://host-1:6650,pulsar://host-2:6650`,
+	}
+
+	failureDomainsCmd.AddCommand(failuresDomainsCreate(c))
+	failureDomainsCmd.AddCommand(failuresDomainsUpdate(c))
+	failureDomainsCmd.AddCommand(failuresDomainsList(c))
+	failureDomainsCmd.AddCommand(failuresDomainsGet(c))
+	failureDomainsCmd.AddCommand(failuresDomainsDelete(c))
+	return failureDomainsCmd
 }
 
-func failuresDomainsCreate() {
-	failureDomain := FailureDomain{}
+func failuresDomainsCreate(c *cli) *cobra.Command {
+	failureDomain := admin.FailureDomain{}
 
 	var createCmd = &cobra.Command{
 		Use:   "create",
@@ -31,22 +31,26 @@ func failuresDomainsCreate() {
 		--brokers pulsar://host-1:6650,pulsar://host-2:6650`,
 		Args: cobra.ExactArgs(2),
 
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterName := args[0]
 			domainName := args[1]
 
-			RestPost(clusterFailureDomainsPath(clusterName, domainName), failureDomain)
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.FailureDomains().Create(c.ctx, clusterName, domainName, failureDomain)
 		},
 	}
 
 	createCmd.Flags().StringSliceVarP(&failureDomain.Brokers, "brokers", "b", nil,
 		"Comma separated list of brokers to be included in the domain")
 
-	failureDomainsCmd.AddCommand(createCmd)
+	return createCmd
 }
 
-func failuresDomainsUpdate() {
-	failureDomain := FailureDomain{}
+func failuresDomainsUpdate(c *cli) *cobra.Command {
+	failureDomain := admin.FailureDomain{}
 
 	var updateCmd = &cobra.Command{
 		Use:   "update",
@@ -55,84 +59,97 @@ func failuresDomainsUpdate() {
 		--brokers pulsar://host-1:6650,pulsar://host-2:6650`,
 		Args: cobra.ExactArgs(2),
 
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterName := args[0]
 			domainName := args[1]
 
-			RestPost(clusterFailureDomainsPath(clusterName, domainName), failureDomain)
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.FailureDomains().Update(c.ctx, clusterName, domainName, failureDomain)
 		},
 	}
 
 	updateCmd.Flags().StringSliceVarP(&failureDomain.Brokers, "brokers", "b", nil,
 		"Comma separated list of brokers to be included in the domain")
 
-	failureDomainsCmd.AddCommand(updateCmd)
+	return updateCmd
 }
 
-func failuresDomainsList() {
+func failuresDomainsList(c *cli) *cobra.Command {
 	var listCmd = &cobra.Command{
 		Use:     "list",
 		Short:   "List existing failure domains for a cluster",
 		Example: `pulsar-ctl clusters failure-domains list us-west`,
 		Args:    cobra.ExactArgs(1),
 
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterName := args[0]
 
-			response := RestGet(clusterFailureDomainsPath(clusterName, ""))
-			var domains map[string]interface{}
-			json.Unmarshal([]byte(response), &domains)
-			for name, _ := range domains {
-				fmt.Println(name)
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			domains, err := client.FailureDomains().List(c.ctx, clusterName)
+			if err != nil {
+				return err
 			}
+
+			return c.printObject(&failureDomainsObject{domains: domains}, "name")
 		},
 	}
 
-	failureDomainsCmd.AddCommand(listCmd)
+	return listCmd
 }
 
-func failuresDomainsGet() {
+func failuresDomainsGet(c *cli) *cobra.Command {
 	var listCmd = &cobra.Command{
 		Use:     "get",
 		Short:   "Get the information regarding a particular failure domain",
 		Example: `pulsar-ctl clusters failure-domains get us-west my-domain`,
 		Args:    cobra.ExactArgs(2),
 
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterName := args[0]
 			domainName := args[1]
 
-			RestPrint(clusterFailureDomainsPath(clusterName, domainName))
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			failureDomain, err := client.FailureDomains().Get(c.ctx, clusterName, domainName)
+			if err != nil {
+				return err
+			}
+			domains := map[string]admin.FailureDomain{domainName: failureDomain}
+			return c.printObject(&failureDomainsObject{domains: domains, single: true}, "json")
 		},
 	}
 
-	failureDomainsCmd.AddCommand(listCmd)
+	return listCmd
 }
 
-func failuresDomainsDelete() {
+func failuresDomainsDelete(c *cli) *cobra.Command {
 	var listCmd = &cobra.Command{
 		Use:     "delete",
-		Short:   "Deletes an existing failure-domain",
+		Short:   "Deletes one or more existing failure-domains",
 		Example: `pulsar-ctl clusters failure-domains delete us-west my-domain`,
-		Args:    cobra.ExactArgs(2),
+		Args:    cobra.MinimumNArgs(2),
 
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterName := args[0]
-			domainName := args[1]
+			domainNames := args[1:]
 
-			RestDelete(clusterFailureDomainsPath(clusterName, domainName))
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return forEachName(c.ctx, domainNames, func(domainName string) error {
+				return client.FailureDomains().Delete(c.ctx, clusterName, domainName)
+			})
 		},
 	}
 
-	failureDomainsCmd.AddCommand(listCmd)
-}
-
-func init() {
-	failuresDomainsCreate()
-	failuresDomainsUpdate()
-	failuresDomainsList()
-	failuresDomainsGet()
-	failuresDomainsDelete()
-
-	clustersCmd.AddCommand(failureDomainsCmd)
+	return listCmd
 }
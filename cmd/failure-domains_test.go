@@ -0,0 +1,39 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+func TestFailureDomains(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddCluster("us-west", admin.ClusterData{ServiceUrl: "http://us-west:8080"})
+
+	c.run("clusters", "failure-domains", "create", "us-west", "rack-1", "--brokers", "broker-1:8080,broker-2:8080")
+	c.run("clusters", "failure-domains", "create", "us-west", "rack-2", "--brokers", "broker-2:8080")
+	c.run("clusters", "failure-domains", "create", "us-west", "rack-2", "--brokers", "broker-3:8080")
+	c.run("clusters", "failure-domains", "get", "us-west", "rack-1")
+	c.run("clusters", "failure-domains", "update", "us-west", "rack-1", "--brokers", "broker-1:8080")
+	c.run("clusters", "failure-domains", "list", "us-west")
+	c.run("clusters", "failure-domains", "list", "us-west", "-o", "table")
+	c.run("clusters", "failure-domains", "delete", "us-west", "rack-1", "rack-3")
+	c.run("clusters", "failure-domains", "list", "us-west", "-o", "name")
+	c.run("clusters", "failure-domains", "list", "us-east")
+	c.check()
+}
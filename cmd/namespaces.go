@@ -0,0 +1,595 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+	"github.com/merlimat/pulsar-ctl/cmd/util"
+	"github.com/merlimat/pulsar-ctl/names"
+	"github.com/spf13/cobra"
+)
+
+// Policies accepted by set-backlog-quota
+var backlogQuotaPolicies = []string{admin.ProducerRequestHold, admin.ProducerException, admin.ConsumerBacklogEviction}
+
+func newNamespacesCommand(c *cli) *cobra.Command {
+	namespacesCmd := &cobra.Command{
+		Use:   "namespaces",
+		Short: "Operations about Pulsar's namespaces",
+		Long: `Manage namespaces
+
+The namespaces are in the form <tenant>/<namespace>, or
+<tenant>/<cluster>/<namespace> for the legacy namespaces of Pulsar 1.x.
+
+For example creating a namespace replicated in two clusters:
+
+    pulsar-ctl namespaces create my-tenant/my-namespace --clusters us-west,us-east
+`,
+	}
+
+	namespacesCmd.AddCommand(namespacesList(c))
+	namespacesCmd.AddCommand(namespacesCreate(c))
+	namespacesCmd.AddCommand(namespacesPolicies(c))
+	namespacesCmd.AddCommand(namespacesDelete(c))
+	namespacesCmd.AddCommand(namespacesGetClusters(c))
+	namespacesCmd.AddCommand(namespacesSetClusters(c))
+	namespacesCmd.AddCommand(namespacesGetRetention(c))
+	namespacesCmd.AddCommand(namespacesSetRetention(c))
+	namespacesCmd.AddCommand(namespacesGetBacklogQuotas(c))
+	namespacesCmd.AddCommand(namespacesSetBacklogQuota(c))
+	namespacesCmd.AddCommand(namespacesRemoveBacklogQuota(c))
+	namespacesCmd.AddCommand(namespacesPermissions(c))
+	namespacesCmd.AddCommand(namespacesGrantPermission(c))
+	namespacesCmd.AddCommand(namespacesRevokePermission(c))
+	return namespacesCmd
+}
+
+func namespacesList(c *cli) *cobra.Command {
+	var listCmd = &cobra.Command{
+		Use:     "list",
+		Short:   "List the namespaces of a tenant",
+		Example: "pulsar-ctl namespaces list my-tenant",
+		Args:    cobra.MaximumNArgs(1),
+
+		// The namespaces of a tenant are listed with the v2 API
+		Annotations: map[string]string{minVersionAnnotation: "2.0.0"},
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenant, err := c.tenantArg(args)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			namespaces, err := client.Namespaces().List(c.ctx, tenant)
+			if err != nil {
+				return err
+			}
+			return c.printNames(namespaces)
+		},
+	}
+
+	return listCmd
+}
+
+func namespacesCreate(c *cli) *cobra.Command {
+	var bundles int
+	var clusters []string
+
+	var createCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create a new namespace",
+		Example: `pulsar-ctl namespaces create my-tenant/my-namespace
+pulsar-ctl namespaces create my-tenant/my-namespace --bundles 16 --clusters us-west,us-east`,
+		Args: cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := names.NamespaceNameParse(args[0])
+			if err != nil {
+				return newUsageError(err)
+			}
+			if bundles < 0 {
+				return newUsageError(errors.New("the number of bundles must be positive"))
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.Namespaces().Create(c.ctx, namespace, bundles, clusters)
+		},
+	}
+
+	createCmd.Flags().IntVarP(&bundles, "bundles", "b", 0,
+		"Number of bundles to split the namespace in. If 0, the default of the brokers")
+	createCmd.Flags().StringSliceVarP(&clusters, "clusters", "c", nil,
+		"Comma separated clusters to replicate the namespace in. If empty, the local cluster")
+
+	return createCmd
+}
+
+func namespacesPolicies(c *cli) *cobra.Command {
+	var policiesCmd = &cobra.Command{
+		Use:     "policies",
+		Short:   "Get the policies of a namespace",
+		Example: "pulsar-ctl namespaces policies my-tenant/my-namespace",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			policies, err := client.Namespaces().Policies(c.ctx, namespace)
+			if err != nil {
+				return err
+			}
+			return c.printObject(&policiesObject{name: namespace.String(), policies: policies}, "json")
+		},
+	}
+
+	return policiesCmd
+}
+
+func namespacesDelete(c *cli) *cobra.Command {
+	var deleteCmd = &cobra.Command{
+		Use:     "delete",
+		Short:   "Delete one or more empty namespaces",
+		Example: "pulsar-ctl namespaces delete my-tenant/my-namespace",
+		Args:    cobra.MinimumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Check all the names before deleting anything
+			namespaces := map[string]*names.NamespaceName{}
+			for _, name := range args {
+				namespace, err := names.NamespaceNameParse(name)
+				if err != nil {
+					return newUsageError(err)
+				}
+				namespaces[name] = namespace
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return forEachName(c.ctx, args, func(name string) error {
+				return client.Namespaces().Delete(c.ctx, namespaces[name])
+			})
+		},
+	}
+
+	return deleteCmd
+}
+
+func namespacesGetClusters(c *cli) *cobra.Command {
+	var getCmd = &cobra.Command{
+		Use:     "get-clusters",
+		Short:   "Get the clusters a namespace is replicated in",
+		Example: "pulsar-ctl namespaces get-clusters my-tenant/my-namespace",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			clusters, err := client.Namespaces().GetReplicationClusters(c.ctx, namespace)
+			if err != nil {
+				return err
+			}
+			return c.printNames(clusters)
+		},
+	}
+
+	return getCmd
+}
+
+func namespacesSetClusters(c *cli) *cobra.Command {
+	var clusters []string
+
+	var setCmd = &cobra.Command{
+		Use:   "set-clusters",
+		Short: "Set the clusters a namespace is replicated in",
+		Long: `Set the clusters a namespace is replicated in
+
+The clusters must exist and the tenant of the namespace must be allowed to
+use them.`,
+		Example: "pulsar-ctl namespaces set-clusters my-tenant/my-namespace --clusters us-west,us-east",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+			if len(clusters) == 0 {
+				return newUsageError(errors.New("at least one cluster must be given"))
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			if err := validateReplicationClusters(c.ctx, client, namespace, clusters); err != nil {
+				return err
+			}
+			return client.Namespaces().SetReplicationClusters(c.ctx, namespace, clusters)
+		},
+	}
+
+	setCmd.Flags().StringSliceVarP(&clusters, "clusters", "c", nil,
+		"Comma separated clusters to replicate the namespace in")
+
+	setCmd.MarkFlagRequired("clusters")
+	return setCmd
+}
+
+// validateReplicationClusters checks that the clusters exist and that the
+// tenant of the namespace is allowed to use them, to report the mistakes
+// before the brokers reject the change
+func validateReplicationClusters(ctx context.Context, client *admin.Client, namespace *names.NamespaceName,
+	clusters []string) error {
+	existing, err := GetClustersList(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	tenant, err := client.Tenants().Get(ctx, namespace.Tenant())
+	if err != nil {
+		return err
+	}
+
+	for _, cluster := range clusters {
+		if !containsString(existing, cluster) {
+			return fmt.Errorf("cluster '%s' does not exist, the clusters are: %s",
+				cluster, joinOrNone(existing))
+		}
+		if !containsString(tenant.AllowedClusters, cluster) {
+			return fmt.Errorf("cluster '%s' is not allowed for tenant '%s', the allowed clusters are: %s",
+				cluster, namespace.Tenant(), joinOrNone(tenant.AllowedClusters))
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func joinOrNone(list []string) string {
+	if len(list) == 0 {
+		return "<none>"
+	}
+	return strings.Join(list, ", ")
+}
+
+func namespacesGetRetention(c *cli) *cobra.Command {
+	var getCmd = &cobra.Command{
+		Use:     "get-retention",
+		Short:   "Get the retention of a namespace",
+		Example: "pulsar-ctl namespaces get-retention my-tenant/my-namespace",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			retention, err := client.Namespaces().GetRetention(c.ctx, namespace)
+			if err != nil {
+				return err
+			}
+			return c.printObject(&retentionObject{name: namespace.String(), retention: retention}, "json")
+		},
+	}
+
+	return getCmd
+}
+
+func namespacesSetRetention(c *cli) *cobra.Command {
+	var retentionTime string
+	var retentionSize string
+
+	var setCmd = &cobra.Command{
+		Use:   "set-retention",
+		Short: "Set the retention of a namespace",
+		Long: `Set the retention of a namespace
+
+The acknowledged messages are kept until both the time and the size limits
+are exceeded. -1 means no limit and 0 disables the retention.`,
+		Example: "pulsar-ctl namespaces set-retention my-tenant/my-namespace --time 7d --size 10G",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+
+			seconds, err := util.ParseRelativeTime(retentionTime)
+			if err != nil {
+				return newUsageError(err)
+			}
+			bytes, err := util.ParseSize(retentionSize)
+			if err != nil {
+				return newUsageError(err)
+			}
+
+			// The brokers take minutes and megabytes: reject the values that
+			// would be rounded, rather than setting a different retention
+			retention := admin.RetentionPolicies{RetentionTimeInMinutes: -1, RetentionSizeInMB: -1}
+			if seconds >= 0 {
+				if seconds%60 != 0 {
+					return newUsageError(fmt.Errorf("the retention time must be a whole number of minutes, got %s", retentionTime))
+				}
+				retention.RetentionTimeInMinutes = int(seconds / 60)
+			}
+			if bytes >= 0 {
+				if bytes%(1<<20) != 0 {
+					return newUsageError(fmt.Errorf("the retention size must be a whole number of megabytes, got %s", retentionSize))
+				}
+				retention.RetentionSizeInMB = bytes >> 20
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.Namespaces().SetRetention(c.ctx, namespace, retention)
+		},
+	}
+
+	setCmd.Flags().StringVarP(&retentionTime, "time", "t", "",
+		"Retention time, with an optional unit: s, m, h, d or w. eg: 7d. It must be whole minutes")
+	setCmd.Flags().StringVarP(&retentionSize, "size", "s", "",
+		"Retention size, with an optional unit: k, m, g or t. eg: 10G. It must be whole megabytes")
+
+	setCmd.MarkFlagRequired("time")
+	setCmd.MarkFlagRequired("size")
+	return setCmd
+}
+
+func namespacesGetBacklogQuotas(c *cli) *cobra.Command {
+	var getCmd = &cobra.Command{
+		Use:     "get-backlog-quotas",
+		Short:   "Get the backlog quotas of a namespace",
+		Example: "pulsar-ctl namespaces get-backlog-quotas my-tenant/my-namespace",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			quotas, err := client.Namespaces().GetBacklogQuotas(c.ctx, namespace)
+			if err != nil {
+				return err
+			}
+			return c.printObject(backlogQuotasObject(quotas), "json")
+		},
+	}
+
+	return getCmd
+}
+
+func namespacesSetBacklogQuota(c *cli) *cobra.Command {
+	var limit string
+	var policy string
+
+	var setCmd = &cobra.Command{
+		Use:   "set-backlog-quota",
+		Short: "Set the backlog quota of a namespace",
+		Long: `Set the backlog quota of a namespace
+
+The policy sets what happens when the backlog of a topic exceeds the limit:
+
+    producer_request_hold       the producers are blocked until there is space
+    producer_exception          the producers get an error
+    consumer_backlog_eviction   the oldest messages of the backlog are discarded`,
+		Example: "pulsar-ctl namespaces set-backlog-quota my-tenant/my-namespace --limit 10G --policy producer_request_hold",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+
+			bytes, err := util.ParseSize(limit)
+			if err != nil {
+				return newUsageError(err)
+			}
+
+			if !containsString(backlogQuotaPolicies, policy) {
+				return newUsageError(fmt.Errorf("invalid policy '%s', expected one of %s", policy, strings.Join(backlogQuotaPolicies, ", ")))
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.Namespaces().SetBacklogQuota(c.ctx, namespace, admin.BacklogQuota{Limit: bytes, Policy: policy})
+		},
+	}
+
+	setCmd.Flags().StringVarP(&limit, "limit", "l", "",
+		"Size limit of the backlog, with an optional unit: k, m, g or t. eg: 10G")
+	setCmd.Flags().StringVarP(&policy, "policy", "p", "",
+		"What happens when the limit is exceeded: "+strings.Join(backlogQuotaPolicies, ", "))
+
+	setCmd.MarkFlagRequired("limit")
+	setCmd.MarkFlagRequired("policy")
+	return setCmd
+}
+
+func namespacesRemoveBacklogQuota(c *cli) *cobra.Command {
+	var removeCmd = &cobra.Command{
+		Use:     "remove-backlog-quota",
+		Short:   "Remove the backlog quota of a namespace, to use the default of the brokers",
+		Example: "pulsar-ctl namespaces remove-backlog-quota my-tenant/my-namespace",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.Namespaces().RemoveBacklogQuota(c.ctx, namespace)
+		},
+	}
+
+	return removeCmd
+}
+
+func namespacesPermissions(c *cli) *cobra.Command {
+	var permissionsCmd = &cobra.Command{
+		Use:     "permissions",
+		Short:   "Get the permissions of the roles on a namespace",
+		Example: "pulsar-ctl namespaces permissions my-tenant/my-namespace",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			permissions, err := client.Namespaces().Permissions(c.ctx, namespace)
+			if err != nil {
+				return err
+			}
+			return c.printObject(permissionsObject(permissions), "json")
+		},
+	}
+
+	return permissionsCmd
+}
+
+func namespacesGrantPermission(c *cli) *cobra.Command {
+	var role string
+	var actions []string
+
+	var grantCmd = &cobra.Command{
+		Use:     "grant-permission",
+		Short:   "Allow actions to a role on a namespace",
+		Example: "pulsar-ctl namespaces grant-permission my-tenant/my-namespace --role my-app --actions produce,consume",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+			if err := validateActions(actions); err != nil {
+				return err
+			}
+			if err := c.checkActionsVersion(actions); err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.Namespaces().GrantPermission(c.ctx, namespace, role, actions)
+		},
+	}
+
+	grantCmd.Flags().StringVar(&role, "role", "",
+		"Role to grant the permission to, eg: the subject of its token")
+	grantCmd.Flags().StringSliceVar(&actions, "actions", nil, actionsHelp)
+
+	grantCmd.MarkFlagRequired("role")
+	grantCmd.MarkFlagRequired("actions")
+	return grantCmd
+}
+
+func namespacesRevokePermission(c *cli) *cobra.Command {
+	var role string
+
+	var revokeCmd = &cobra.Command{
+		Use:     "revoke-permission",
+		Short:   "Revoke all the permissions of a role on a namespace",
+		Example: "pulsar-ctl namespaces revoke-permission my-tenant/my-namespace --role my-app",
+		Args:    cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.Namespaces().RevokePermission(c.ctx, namespace, role)
+		},
+	}
+
+	revokeCmd.Flags().StringVar(&role, "role", "", "Role to revoke the permissions of")
+
+	revokeCmd.MarkFlagRequired("role")
+	return revokeCmd
+}
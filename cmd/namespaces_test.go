@@ -0,0 +1,104 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+func TestNamespaces(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddCluster("us-west", admin.ClusterData{})
+	c.server.AddCluster("us-east", admin.ClusterData{})
+	c.server.AddTenant("public", admin.TenantInfo{AllowedClusters: []string{"us-west", "us-east"}})
+	c.server.AddNamespace("public/default")
+	c.server.AddTopic("persistent://public/default/orders")
+
+	c.run("namespaces", "list", "public")
+	c.run("namespaces", "create", "public/events", "--bundles", "2", "--clusters", "us-west,us-east")
+	c.run("namespaces", "create", "public/events")
+	c.run("namespaces", "create", "public/logs", "--clusters", "us-central")
+	c.run("namespaces", "create", "public/bad:name!")
+	c.run("namespaces", "create", "public/logs", "--bundles", "-1")
+	c.run("namespaces", "create", "public/us-west/legacy", "--bundles", "8")
+	c.run("namespaces", "list", "public", "-o", "json")
+	c.run("namespaces", "policies", "public/events")
+	c.run("namespaces", "policies", "public/us-west/legacy", "-o", "wide")
+	c.run("namespaces", "policies", "public/missing")
+	c.run("namespaces", "delete", "public/events", "public/default", "public/us-west/legacy")
+	c.run("namespaces", "delete", "public/events", "public")
+	c.run("namespaces", "list")
+	c.run("config", "set-context", "local", "--tenant", "public", "--namespace", "default")
+	c.run("config", "use-context", "local")
+	c.run("namespaces", "list")
+	c.run("namespaces", "policies", "-o", "yaml")
+	c.check()
+}
+
+func TestNamespacesRetentionAndBacklogQuota(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddTenant("public", admin.TenantInfo{})
+	c.server.AddNamespace("public/default")
+
+	c.run("namespaces", "get-retention", "public/default")
+	c.run("namespaces", "set-retention", "public/default", "--time", "7d", "--size", "10G")
+	c.run("namespaces", "get-retention", "public/default")
+	c.run("namespaces", "get-retention", "public/default", "-o", "table")
+	c.run("namespaces", "set-retention", "public/default", "--time", "-1", "--size", "500k")
+	c.run("namespaces", "set-retention", "public/default", "--time", "90s", "--size", "1G")
+	c.run("namespaces", "set-retention", "public/default", "--time", "120s", "--size", "1536k")
+	c.run("namespaces", "set-retention", "public/default", "--time", "1y", "--size", "1G")
+	c.run("namespaces", "set-retention", "public/default", "--time", "90m")
+	c.run("namespaces", "set-retention", "public/missing", "--time", "1h", "--size", "1G")
+
+	c.run("namespaces", "get-backlog-quotas", "public/default")
+	c.run("namespaces", "set-backlog-quota", "public/default", "--limit", "20G", "--policy", "producer_exception")
+	c.run("namespaces", "set-backlog-quota", "public/default", "--limit", "2G", "--policy", "producer_request_hold")
+	c.run("namespaces", "set-backlog-quota", "public/default", "--limit", "2G", "--policy", "drop")
+	c.run("namespaces", "get-backlog-quotas", "public/default", "-o", "table")
+	c.run("namespaces", "get-backlog-quotas", "public/default", "-o", "jsonpath={.destination_storage.limit}")
+	c.run("namespaces", "get-backlog-quotas", "public/default", "-o", "go-template={{.destination_storage.limit}}")
+	c.run("namespaces", "get-backlog-quotas", "public/default", "-o", "xml")
+	c.run("namespaces", "set-retention", "public/default", "--time", "1d", "--size", "1G")
+	c.run("namespaces", "remove-backlog-quota", "public/default")
+	c.run("namespaces", "get-backlog-quotas", "public/default")
+	c.run("namespaces", "set-retention", "public/default", "--time", "1d", "--size", "1G", "--dry-run")
+	c.check()
+}
+
+func TestNamespacesReplicationClusters(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddCluster("us-west", admin.ClusterData{})
+	c.server.AddCluster("us-east", admin.ClusterData{})
+	c.server.AddCluster("eu-central", admin.ClusterData{})
+	c.server.AddTenant("public", admin.TenantInfo{AllowedClusters: []string{"us-west", "us-east"}})
+	c.server.AddNamespace("public/default")
+
+	c.run("namespaces", "get-clusters", "public/default")
+	c.run("namespaces", "set-clusters", "public/default", "--clusters", "us-west,us-east")
+	c.run("namespaces", "get-clusters", "public/default")
+	c.run("namespaces", "set-clusters", "public/default", "--clusters", "us-west,ap-south")
+	c.run("namespaces", "set-clusters", "public/default", "--clusters", "eu-central")
+	c.run("namespaces", "set-clusters", "public/default", "--clusters", "")
+	c.run("namespaces", "set-clusters", "missing/default", "--clusters", "us-west")
+	c.run("namespaces", "set-clusters", "public/missing", "--clusters", "us-west")
+	c.run("namespaces", "get-clusters", "public/default", "-o", "json")
+	c.check()
+}
@@ -0,0 +1,361 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+	"github.com/merlimat/pulsar-ctl/cmd/printer"
+	"github.com/merlimat/pulsar-ctl/cmd/util"
+)
+
+// printObject writes the object to stdout in the format selected with
+// --output, or in the given default format
+func (c *cli) printObject(obj printer.Object, defaultFormat string) error {
+	format := c.outputFormat
+	if format == "" {
+		format = defaultFormat
+	}
+
+	p, err := printer.New(format)
+	if err != nil {
+		return err
+	}
+	return p.Print(c.Stdout, obj)
+}
+
+// printNames prints a list of resource names, one per line by default
+func (c *cli) printNames(names []string) error {
+	return c.printObject(nameList(names), "name")
+}
+
+type nameList []string
+
+func (l nameList) Data() interface{} {
+	return []string(l)
+}
+
+func (l nameList) Header(wide bool) []string {
+	return []string{"NAME"}
+}
+
+func (l nameList) Rows(wide bool) [][]string {
+	rows := make([][]string, len(l))
+	for i, name := range l {
+		rows[i] = []string{name}
+	}
+	return rows
+}
+
+func (l nameList) Names() []string {
+	return l
+}
+
+type clusterObject struct {
+	name    string
+	cluster admin.ClusterData
+}
+
+func (c *clusterObject) Data() interface{} {
+	return c.cluster
+}
+
+func (c *clusterObject) Header(wide bool) []string {
+	header := []string{"NAME", "SERVICE-URL", "BROKER-SERVICE-URL"}
+	if wide {
+		header = append(header, "SERVICE-URL-TLS", "BROKER-SERVICE-URL-TLS", "PEER-CLUSTERS")
+	}
+	return header
+}
+
+func (c *clusterObject) Rows(wide bool) [][]string {
+	row := []string{c.name, c.cluster.ServiceUrl, c.cluster.BrokerServiceUrl}
+	if wide {
+		row = append(row, c.cluster.ServiceUrlTls, c.cluster.BrokerServiceUrlTls,
+			strings.Join(c.cluster.PeerClusterNames, ","))
+	}
+	return [][]string{row}
+}
+
+func (c *clusterObject) Names() []string {
+	return []string{c.name}
+}
+
+type tenantObject struct {
+	name   string
+	tenant admin.TenantInfo
+}
+
+func (t *tenantObject) Data() interface{} {
+	return t.tenant
+}
+
+func (t *tenantObject) Header(wide bool) []string {
+	return []string{"NAME", "ADMIN-ROLES", "ALLOWED-CLUSTERS"}
+}
+
+func (t *tenantObject) Rows(wide bool) [][]string {
+	return [][]string{{
+		t.name,
+		strings.Join(t.tenant.AdminRoles, ","),
+		strings.Join(t.tenant.AllowedClusters, ","),
+	}}
+}
+
+func (t *tenantObject) Names() []string {
+	return []string{t.name}
+}
+
+type policiesObject struct {
+	name     string
+	policies admin.Policies
+}
+
+func (p *policiesObject) Data() interface{} {
+	return p.policies
+}
+
+func (p *policiesObject) Header(wide bool) []string {
+	header := []string{"NAME", "BUNDLES", "REPLICATION-CLUSTERS"}
+	if wide {
+		header = append(header, "MESSAGE-TTL", "ANTI-AFFINITY-GROUP")
+	}
+	return header
+}
+
+func (p *policiesObject) Rows(wide bool) [][]string {
+	bundles := ""
+	if p.policies.Bundles != nil {
+		bundles = strconv.Itoa(p.policies.Bundles.NumBundles)
+	}
+
+	row := []string{p.name, bundles, strings.Join(p.policies.ReplicationClusters, ",")}
+	if wide {
+		row = append(row, strconv.Itoa(p.policies.MessageTTLInSeconds), p.policies.AntiAffinityGroup)
+	}
+	return [][]string{row}
+}
+
+func (p *policiesObject) Names() []string {
+	return []string{p.name}
+}
+
+type retentionObject struct {
+	name string
+
+	// nil when the retention of the namespace is not set
+	retention *admin.RetentionPolicies
+}
+
+func (r *retentionObject) Data() interface{} {
+	return r.retention
+}
+
+func (r *retentionObject) Header(wide bool) []string {
+	return []string{"NAME", "TIME", "SIZE"}
+}
+
+func (r *retentionObject) Rows(wide bool) [][]string {
+	if r.retention == nil {
+		return [][]string{{r.name, "", ""}}
+	}
+
+	time := int64(r.retention.RetentionTimeInMinutes)
+	if time > 0 {
+		time *= 60
+	}
+	size := r.retention.RetentionSizeInMB
+	if size > 0 {
+		size <<= 20
+	}
+	return [][]string{{r.name, util.FormatRelativeTime(time), util.FormatSize(size)}}
+}
+
+func (r *retentionObject) Names() []string {
+	return []string{r.name}
+}
+
+// backlogQuotasObject holds the backlog quotas of a namespace, by type
+type backlogQuotasObject map[string]admin.BacklogQuota
+
+func (b backlogQuotasObject) Data() interface{} {
+	return map[string]admin.BacklogQuota(b)
+}
+
+func (b backlogQuotasObject) Header(wide bool) []string {
+	return []string{"TYPE", "LIMIT", "POLICY"}
+}
+
+func (b backlogQuotasObject) Rows(wide bool) [][]string {
+	rows := [][]string{}
+	for _, name := range b.Names() {
+		rows = append(rows, []string{name, util.FormatSize(b[name].Limit), b[name].Policy})
+	}
+	return rows
+}
+
+func (b backlogQuotasObject) Names() []string {
+	names := make([]string, 0, len(b))
+	for name := range b {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// permissionsObject holds the actions allowed to each role
+type permissionsObject map[string][]string
+
+func (p permissionsObject) Data() interface{} {
+	return map[string][]string(p)
+}
+
+func (p permissionsObject) Header(wide bool) []string {
+	return []string{"ROLE", "ACTIONS"}
+}
+
+func (p permissionsObject) Rows(wide bool) [][]string {
+	rows := [][]string{}
+	for _, role := range p.Names() {
+		rows = append(rows, []string{role, strings.Join(p[role], ",")})
+	}
+	return rows
+}
+
+func (p permissionsObject) Names() []string {
+	roles := make([]string, 0, len(p))
+	for role := range p {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// failureDomainsObject holds one or more failure domains of a cluster
+type failureDomainsObject struct {
+	domains map[string]admin.FailureDomain
+
+	// single is set when a single domain was requested, to print it without
+	// the enclosing map
+	single bool
+}
+
+func (f *failureDomainsObject) Data() interface{} {
+	if f.single {
+		for _, domain := range f.domains {
+			return domain
+		}
+	}
+	return f.domains
+}
+
+func (f *failureDomainsObject) Header(wide bool) []string {
+	return []string{"NAME", "BROKERS"}
+}
+
+func (f *failureDomainsObject) Rows(wide bool) [][]string {
+	rows := [][]string{}
+	for _, name := range f.Names() {
+		rows = append(rows, []string{name, strings.Join(f.domains[name].Brokers, ",")})
+	}
+	return rows
+}
+
+func (f *failureDomainsObject) Names() []string {
+	names := make([]string, 0, len(f.domains))
+	for name := range f.domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rawObject is an arbitrary JSON document, eg: a response of the api command
+type rawObject struct {
+	data interface{}
+}
+
+func (r *rawObject) Data() interface{} {
+	return convertNumbers(r.data)
+}
+
+// convertNumbers replaces the json.Number values of a decoded document with
+// int64 or float64, so that they are also printed as numbers in YAML
+func convertNumbers(value interface{}) interface{} {
+	switch value := value.(type) {
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return n
+		}
+		if f, err := value.Float64(); err == nil {
+			return f
+		}
+		return value.String()
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, element := range value {
+			converted[i] = convertNumbers(element)
+		}
+		return converted
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for key, element := range value {
+			converted[key] = convertNumbers(element)
+		}
+		return converted
+	default:
+		return value
+	}
+}
+
+func (r *rawObject) Header(wide bool) []string {
+	return []string{"VALUE"}
+}
+
+// Rows returns a row for each element of an array, or a single row
+func (r *rawObject) Rows(wide bool) [][]string {
+	values, ok := r.data.([]interface{})
+	if !ok {
+		values = []interface{}{r.data}
+	}
+
+	rows := make([][]string, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			rows[i] = []string{s}
+		} else {
+			data, _ := json.Marshal(value)
+			rows[i] = []string{string(data)}
+		}
+	}
+	return rows
+}
+
+// Names returns the strings of an array, eg: a list of resource names
+func (r *rawObject) Names() []string {
+	names := []string{}
+	if values, ok := r.data.([]interface{}); ok {
+		for _, value := range values {
+			if name, ok := value.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,62 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+// Actions accepted by the grant-permission commands
+var authActions = []string{admin.ActionProduce, admin.ActionConsume, admin.ActionFunctions,
+	admin.ActionSources, admin.ActionSinks, admin.ActionPackages}
+
+var actionsHelp = "Comma separated actions to allow: " + strings.Join(authActions, ", ")
+
+// Minimum version of Pulsar for the actions added after 2.0
+var actionMinVersions = map[string]string{
+	// The package management service appeared in Pulsar 2.8
+	admin.ActionPackages: "2.8.0",
+}
+
+// validateActions checks that the actions are known to the brokers, to not
+// grant a misspelled permission
+func validateActions(actions []string) error {
+	if len(actions) == 0 {
+		return newUsageError(errors.New("at least one action must be given"))
+	}
+
+	for _, action := range actions {
+		if !containsString(authActions, action) {
+			return newUsageError(fmt.Errorf("invalid action '%s', expected one of %s", action, strings.Join(authActions, ", ")))
+		}
+	}
+	return nil
+}
+
+// checkActionsVersion fails if the cluster is too old for any of the actions
+func (c *cli) checkActionsVersion(actions []string) error {
+	for _, action := range actions {
+		if required, ok := actionMinVersions[action]; ok {
+			if err := c.requireVersion("the "+action+" action", required); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+func TestPermissions(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddTenant("public", admin.TenantInfo{})
+	c.server.AddNamespace("public/default")
+	c.server.AddNamespace("public/us-west/legacy")
+	c.server.AddTopic("persistent://public/default/orders")
+
+	c.run("namespaces", "permissions", "public/default")
+	c.run("namespaces", "grant-permission", "public/default", "--role", "app", "--actions", "produce,consume")
+	c.run("namespaces", "grant-permission", "public/default", "--role", "ops", "--actions", "functions")
+	c.run("namespaces", "grant-permission", "public/default", "--role", "ops", "--actions", "produce,admin")
+	c.run("namespaces", "grant-permission", "public/default", "--actions", "produce")
+	c.run("namespaces", "permissions", "public/default", "-o", "table")
+
+	c.run("topics", "grant-permission", "orders", "--role", "reader", "--actions", "consume")
+	c.run("topics", "grant-permission", "persistent://public/us-west/legacy/events", "--role", "reader", "--actions", "consume")
+	c.run("topics", "permissions", "persistent://public/default/orders")
+	c.run("topics", "permissions", "persistent://public/us-west/legacy/events", "-o", "table")
+	c.run("topics", "permissions", "persistent://public/default")
+	c.run("topics", "revoke-permission", "orders", "--role", "reader")
+	c.run("topics", "revoke-permission", "orders", "--role", "reader")
+
+	c.run("namespaces", "revoke-permission", "public/default", "--role", "app")
+	c.run("namespaces", "permissions", "public/default", "-o", "table")
+	c.check()
+}
@@ -0,0 +1,229 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a subset of the JSONPath templates supported by kubectl:
+// literal text mixed with {expressions}, where an expression is a sequence
+// of .field, .*, [index] and [*] selectors, eg: {.items[*].name}
+type jsonPath struct {
+	segments []jsonPathSegment
+}
+
+type jsonPathSegment struct {
+	text  string
+	steps []jsonPathStep // nil for literal text
+}
+
+type jsonPathStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+func parseJsonPath(template string) (*jsonPath, error) {
+	path := &jsonPath{}
+
+	for len(template) > 0 {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			path.segments = append(path.segments, jsonPathSegment{text: template})
+			break
+		}
+		if start > 0 {
+			path.segments = append(path.segments, jsonPathSegment{text: template[:start]})
+		}
+
+		end := strings.Index(template[start:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid jsonpath '%s': unclosed expression", template)
+		}
+
+		steps, err := parseJsonPathExpression(template[start+1 : start+end])
+		if err != nil {
+			return nil, err
+		}
+		path.segments = append(path.segments, jsonPathSegment{steps: steps})
+		template = template[start+end+1:]
+	}
+
+	return path, nil
+}
+
+func parseJsonPathExpression(expr string) ([]jsonPathStep, error) {
+	original := expr
+	steps := []jsonPathStep{}
+
+	// The leading "$" or "." refer to the root object
+	expr = strings.TrimPrefix(expr, "$")
+	if expr == "." {
+		return steps, nil
+	}
+
+	for len(expr) > 0 {
+		switch expr[0] {
+		case '.':
+			expr = expr[1:]
+			end := strings.IndexAny(expr, ".[")
+			if end < 0 {
+				end = len(expr)
+			}
+			field := expr[:end]
+			if field == "" {
+				return nil, fmt.Errorf("invalid jsonpath expression '%s': empty field name", original)
+			}
+			steps = append(steps, jsonPathStep{field: field, wildcard: field == "*"})
+			expr = expr[end:]
+
+		case '[':
+			end := strings.Index(expr, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid jsonpath expression '%s': unclosed bracket", original)
+			}
+			selector := expr[1:end]
+			expr = expr[end+1:]
+
+			if selector == "*" {
+				steps = append(steps, jsonPathStep{wildcard: true})
+				continue
+			}
+
+			if quoted := strings.Trim(selector, "'\""); quoted != selector {
+				steps = append(steps, jsonPathStep{field: quoted})
+				continue
+			}
+
+			index, err := strconv.Atoi(selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid jsonpath expression '%s': invalid index '%s'", original, selector)
+			}
+			steps = append(steps, jsonPathStep{index: index, isIndex: true})
+
+		default:
+			return nil, fmt.Errorf("invalid jsonpath expression '%s': expected '.' or '['", original)
+		}
+	}
+
+	return steps, nil
+}
+
+// evaluateJsonPath returns the values selected by the steps, starting from data
+func evaluateJsonPath(steps []jsonPathStep, data interface{}) ([]interface{}, error) {
+	values := []interface{}{data}
+
+	for _, step := range steps {
+		next := []interface{}{}
+		for _, value := range values {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				if step.wildcard {
+					keys := make([]string, 0, len(v))
+					for key := range v {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						next = append(next, v[key])
+					}
+				} else if step.isIndex {
+					return nil, fmt.Errorf("jsonpath: cannot index an object with [%d]", step.index)
+				} else if field, ok := v[step.field]; ok {
+					next = append(next, field)
+				} else {
+					return nil, fmt.Errorf("jsonpath: field '%s' not found", step.field)
+				}
+
+			case []interface{}:
+				if step.wildcard {
+					next = append(next, v...)
+				} else if step.isIndex {
+					index := step.index
+					if index < 0 {
+						index += len(v)
+					}
+					if index < 0 || index >= len(v) {
+						return nil, fmt.Errorf("jsonpath: index [%d] out of range", step.index)
+					}
+					next = append(next, v[index])
+				} else {
+					return nil, fmt.Errorf("jsonpath: cannot select field '%s' on a list", step.field)
+				}
+
+			default:
+				return nil, fmt.Errorf("jsonpath: cannot select into a %T value", value)
+			}
+		}
+		values = next
+	}
+
+	return values, nil
+}
+
+type jsonPathPrinter struct {
+	path *jsonPath
+}
+
+func (p *jsonPathPrinter) Print(w io.Writer, obj Object) error {
+	data, err := genericData(obj)
+	if err != nil {
+		return err
+	}
+
+	var out strings.Builder
+	for _, segment := range p.path.segments {
+		if segment.steps == nil {
+			out.WriteString(segment.text)
+			continue
+		}
+
+		values, err := evaluateJsonPath(segment.steps, data)
+		if err != nil {
+			return err
+		}
+
+		for i, value := range values {
+			if i > 0 {
+				out.WriteString(" ")
+			}
+			out.WriteString(formatJsonPathValue(value))
+		}
+	}
+
+	_, err = fmt.Fprintln(w, out.String())
+	return err
+}
+
+func formatJsonPathValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJsonPath(t *testing.T) {
+	for _, test := range []struct {
+		template string
+		expected string
+	}{
+		{"{[0].name}", "us-east"},
+		{"{$[1].name}", "us-west"},
+		{"{[-1].name}", "us-west"},
+		{"{[0]['serviceUrl']}", "http://us-east:8080"},
+		{"{[*].name}", "us-east us-west"},
+		{"{[0].brokers[*]}", "b1 b2"},
+		{"{[0].brokers}", `["b1","b2"]`},
+		{"{[1].*}", "us-west 10737418240"},
+		{"{[1].sizeInBytes}", "10737418240"},
+		{"name: {[0].name}, url: {[0].serviceUrl}", "name: us-east, url: http://us-east:8080"},
+		{"no expression", "no expression"},
+	} {
+		printer, err := New("jsonpath=" + test.template)
+		if err != nil {
+			t.Errorf("%s: %s", test.template, err)
+			continue
+		}
+
+		var out bytes.Buffer
+		if err := printer.Print(&out, testClusters); err != nil {
+			t.Errorf("%s: %s", test.template, err)
+		} else if out.String() != test.expected+"\n" {
+			t.Errorf("%s: expected %q, got %q", test.template, test.expected+"\n", out.String())
+		}
+	}
+}
+
+func TestJsonPathEvaluationError(t *testing.T) {
+	for _, test := range []struct {
+		template string
+		err      string
+	}{
+		{"{[0].missing}", "field 'missing' not found"},
+		{"{[*].serviceUrl}", "field 'serviceUrl' not found"},
+		{"{[2].name}", "index [2] out of range"},
+		{"{[-3].name}", "index [-3] out of range"},
+		{"{[0][0]}", "cannot index an object with [0]"},
+		{"{.name}", "cannot select field 'name' on a list"},
+		{"{[0].name.first}", "cannot select into a string value"},
+	} {
+		printer, err := New("jsonpath=" + test.template)
+		if err != nil {
+			t.Errorf("%s: %s", test.template, err)
+			continue
+		}
+
+		var out bytes.Buffer
+		err = printer.Print(&out, testClusters)
+		if err == nil {
+			t.Errorf("%s: expected an error, printed %q", test.template, out.String())
+		} else if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: expected error %q, got %q", test.template, test.err, err)
+		}
+	}
+}
+
+func TestJsonPathParseError(t *testing.T) {
+	for _, test := range []struct {
+		template string
+		err      string
+	}{
+		{"{.name", "unclosed expression"},
+		{"{[0}", "unclosed bracket"},
+		{"{[first]}", "invalid index 'first'"},
+		{"{name}", "expected '.' or '['"},
+		{"{..name}", "empty field name"},
+		{"{.items.}", "empty field name"},
+	} {
+		_, err := New("jsonpath=" + test.template)
+		if err == nil {
+			t.Errorf("%s: expected an error", test.template)
+		} else if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: expected error %q, got %q", test.template, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package printer formats the resources returned by the admin API in the
+// output format selected with the --output flag.
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Object is a resource, or a list of resources, that can be printed in any
+// of the output formats
+type Object interface {
+	// Data returns the value printed by the json, yaml, jsonpath and
+	// go-template formats
+	Data() interface{}
+
+	// Header returns the column names for the table format. The wide format
+	// can include additional columns.
+	Header(wide bool) []string
+
+	// Rows returns the table rows, with one cell per column of the header
+	Rows(wide bool) [][]string
+
+	// Names returns the names of the resources, for the name format
+	Names() []string
+}
+
+// Printer writes objects in a particular output format
+type Printer interface {
+	Print(w io.Writer, obj Object) error
+}
+
+// Formats lists the supported output formats, for the help text
+const Formats = "json|yaml|table|wide|name|jsonpath=<template>|go-template=<template>"
+
+// New returns the printer for the given output format, as passed to --output
+func New(format string) (Printer, error) {
+	name, arg := format, ""
+	if i := strings.Index(format, "="); i >= 0 {
+		name, arg = format[:i], format[i+1:]
+
+		// Only the template formats take an argument, eg: json=x is unknown
+		if name != "jsonpath" && name != "go-template" {
+			name = format
+		}
+	}
+
+	switch name {
+	case "json":
+		return &jsonPrinter{}, nil
+	case "yaml":
+		return &yamlPrinter{}, nil
+	case "table":
+		return &tablePrinter{wide: false}, nil
+	case "wide":
+		return &tablePrinter{wide: true}, nil
+	case "name":
+		return &namePrinter{}, nil
+	case "jsonpath":
+		if arg == "" {
+			return nil, fmt.Errorf("missing template for the jsonpath output format, eg: -o jsonpath={.serviceUrl}")
+		}
+		path, err := parseJsonPath(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathPrinter{path: path}, nil
+	case "go-template":
+		if arg == "" {
+			return nil, fmt.Errorf("missing template for the go-template output format, eg: -o go-template={{.serviceUrl}}")
+		}
+		tmpl, err := template.New("output").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid go-template: %s", err)
+		}
+		return &templatePrinter{template: tmpl}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format '%s', expected one of %s", format, Formats)
+	}
+}
+
+type jsonPrinter struct{}
+
+func (p *jsonPrinter) Print(w io.Writer, obj Object) error {
+	data, err := json.MarshalIndent(obj.Data(), "", "   ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+type yamlPrinter struct{}
+
+func (p *yamlPrinter) Print(w io.Writer, obj Object) error {
+	data, err := json.Marshal(obj.Data())
+	if err != nil {
+		return err
+	}
+
+	// Going through JSON keeps the field names and their order
+	var generic yaml.MapSlice
+	var value interface{} = &generic
+	if len(data) > 0 && data[0] != '{' {
+		value = new(interface{})
+	}
+	if err := yaml.Unmarshal(data, value); err != nil {
+		return err
+	}
+
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+type tablePrinter struct {
+	wide bool
+}
+
+func (p *tablePrinter) Print(w io.Writer, obj Object) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(obj.Header(p.wide), "\t"))
+	for _, row := range obj.Rows(p.wide) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if cell == "" {
+				cell = "<none>"
+			}
+			cells[i] = cell
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+type namePrinter struct{}
+
+func (p *namePrinter) Print(w io.Writer, obj Object) error {
+	for _, name := range obj.Names() {
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type templatePrinter struct {
+	template *template.Template
+}
+
+func (p *templatePrinter) Print(w io.Writer, obj Object) error {
+	data, err := genericData(obj)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := p.template.Execute(&out, data); err != nil {
+		return fmt.Errorf("failed to execute go-template: %s", err)
+	}
+	_, err = fmt.Fprintln(w, out.String())
+	return err
+}
+
+// genericData converts the object data to maps and slices keyed by the JSON
+// field names, which are the names used in the templates. The numbers are
+// kept as json.Number, so that the large integers, like sizes in bytes, are
+// not printed in the float64 notation, eg: 1.073741824e+10.
+func genericData(obj Object) (interface{}, error) {
+	data, err := json.Marshal(obj.Data())
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
@@ -0,0 +1,146 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// testList is a list of clusters, with their service URL in the wide format
+type testList struct {
+	items []map[string]interface{}
+}
+
+func (l *testList) Data() interface{} {
+	return l.items
+}
+
+func (l *testList) Header(wide bool) []string {
+	if wide {
+		return []string{"NAME", "SERVICE URL"}
+	}
+	return []string{"NAME"}
+}
+
+func (l *testList) Rows(wide bool) [][]string {
+	rows := [][]string{}
+	for _, item := range l.items {
+		row := []string{item["name"].(string)}
+		if wide {
+			url, _ := item["serviceUrl"].(string)
+			row = append(row, url)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (l *testList) Names() []string {
+	names := []string{}
+	for _, item := range l.items {
+		names = append(names, item["name"].(string))
+	}
+	return names
+}
+
+var testClusters = &testList{items: []map[string]interface{}{
+	{"name": "us-east", "serviceUrl": "http://us-east:8080", "brokers": []string{"b1", "b2"}},
+	{"name": "us-west", "sizeInBytes": int64(10737418240)},
+}}
+
+func TestNewInvalidFormat(t *testing.T) {
+	for _, test := range []struct {
+		format string
+		err    string
+	}{
+		{"", "unknown output format ''"},
+		{"xml", "unknown output format 'xml'"},
+		{"JSON", "unknown output format 'JSON'"},
+		{"json=x", "unknown output format 'json=x'"},
+		{"jsonpath", "missing template for the jsonpath output format"},
+		{"jsonpath=", "missing template for the jsonpath output format"},
+		{"go-template", "missing template for the go-template output format"},
+		{"go-template={{.name", "invalid go-template"},
+		{"go-template={{.name | nofunc}}", "invalid go-template"},
+	} {
+		_, err := New(test.format)
+		if err == nil {
+			t.Errorf("%q: expected an error", test.format)
+		} else if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%q: expected error %q, got %q", test.format, test.err, err)
+		}
+	}
+}
+
+func TestPrint(t *testing.T) {
+	empty := &testList{items: []map[string]interface{}{}}
+
+	for _, test := range []struct {
+		format   string
+		obj      Object
+		expected string
+	}{
+		{"table", testClusters, "NAME\nus-east\nus-west\n"},
+		{"wide", testClusters, "NAME      SERVICE URL\nus-east   http://us-east:8080\nus-west   <none>\n"},
+		{"name", testClusters, "us-east\nus-west\n"},
+		{"table", empty, "NAME\n"},
+		{"wide", empty, "NAME   SERVICE URL\n"},
+		{"name", empty, ""},
+		{"json", empty, "[]\n"},
+		{"go-template={{range .}}{{.name}} {{end}}", testClusters, "us-east us-west \n"},
+		{"go-template={{(index . 1).sizeInBytes}}", testClusters, "10737418240\n"},
+		{"go-template={{len .}}", empty, "0\n"},
+	} {
+		printer, err := New(test.format)
+		if err != nil {
+			t.Errorf("%s: %s", test.format, err)
+			continue
+		}
+
+		var out bytes.Buffer
+		if err := printer.Print(&out, test.obj); err != nil {
+			t.Errorf("%s: %s", test.format, err)
+		} else if out.String() != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.format, test.expected, out.String())
+		}
+	}
+}
+
+func TestPrintTemplateError(t *testing.T) {
+	for _, test := range []struct {
+		format string
+		err    string
+	}{
+		{"go-template={{index . 5}}", "index out of range"},
+		{"go-template={{range .}}{{.name.first}}{{end}}", "can't evaluate field first"},
+		{"go-template={{template \"missing\"}}", "not defined"},
+	} {
+		printer, err := New(test.format)
+		if err != nil {
+			t.Errorf("%s: %s", test.format, err)
+			continue
+		}
+
+		var out bytes.Buffer
+		err = printer.Print(&out, testClusters)
+		if err == nil {
+			t.Errorf("%s: expected an error, printed %q", test.format, out.String())
+		} else if !strings.HasPrefix(err.Error(), "failed to execute go-template") || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: expected error %q, got %q", test.format, test.err, err)
+		}
+	}
+}
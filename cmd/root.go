@@ -15,77 +15,394 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
-	homedir "github.com/mitchellh/go-homedir"
+	"github.com/merlimat/pulsar-ctl/admin"
+	"github.com/merlimat/pulsar-ctl/cmd/printer"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
+// Configuration keys, named after the ones used in Pulsar's client.conf
+const (
+	webServiceUrlKey                 = "webServiceUrl"
+	urlSelectionKey                  = "urlSelection"
+	tlsTrustCertsFilePathKey         = "tlsTrustCertsFilePath"
+	tlsCertificateFilePathKey        = "tlsCertificateFilePath"
+	tlsKeyFilePathKey                = "tlsKeyFilePath"
+	tlsAllowInsecureConnectionKey    = "tlsAllowInsecureConnection"
+	tlsEnableHostnameVerificationKey = "tlsEnableHostnameVerification"
+	authTokenKey                     = "authToken"
+	authTokenFileKey                 = "authTokenFile"
+	oauth2IssuerUrlKey               = "oauth2IssuerUrl"
+	oauth2AudienceKey                = "oauth2Audience"
+	oauth2PrivateKeyKey              = "oauth2PrivateKey"
+	oauth2ScopeKey                   = "oauth2Scope"
+	authExecCommandKey               = "authExecCommand"
+	authExecArgsKey                  = "authExecArgs"
+	basicAuthUserKey                 = "basicAuthUser"
+	basicAuthPasswordKey             = "basicAuthPassword"
+	basicAuthPasswordFileKey         = "basicAuthPasswordFile"
+	maxRetriesKey                    = "maxRetries"
+	retryTimeoutKey                  = "retryTimeout"
+	retryPostKey                     = "retryPost"
+	dryRunKey                        = "dryRun"
+	verboseKey                       = "verbose"
+	printCurlKey                     = "printCurl"
+	requestTimeoutKey                = "requestTimeout"
+	timeoutKey                       = "timeout"
+)
 
-var configFile string
-var adminUrl string
+// Options customizes a command tree built by NewRootCommand
+type Options struct {
+	// Streams of the commands. They default to the ones of the process.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
 
-// rootCmd represents the base command when called without any subcommands
-var rootCmd = &cobra.Command{
-	Use:   "pulsar-ctl",
-	Short: "Command line tool to manage Pulsar",
-	Long: `Command line tool to manage Pulsar`,
+	// Admin client used by the commands. By default, the client is created
+	// from the flags, the context and the config file.
+	Client *admin.Client
 
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	//	Run: func(cmd *cobra.Command, args []string) { },
+	// Location of the contexts file. It defaults to $PULSARCTL_CONFIG or
+	// ~/.pulsar-ctl/config.
+	ContextsPath string
 }
 
-// Execute adds all child commands to the root command and sets flags appropriately.
-// This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
+// cli holds the state shared by the commands of a tree
+type cli struct {
+	Options
+
+	// Configuration from the flags, the context and the config file
+	viper *viper.Viper
+	flags *pflag.FlagSet
+
+	configFile   string
+	contextName  string
+	outputFormat string
+
+	// Name of the context in use, if any
+	context string
+
+	// Set once the command line has been parsed and validated, so that the
+	// failures before that point are reported as usage errors
+	started bool
+
+	// Passed to all the admin calls of the running command. It is cancelled
+	// when the --timeout expires, or by release() when the command returns.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
-func init() {
-	cobra.OnInitialize(initConfig)
+// NewRootCommand builds a pulsar-ctl command tree. The commands return their
+// errors instead of exiting and the trees don't share any state, so they can
+// be embedded in other tools and run concurrently.
+func NewRootCommand(opts Options) *cobra.Command {
+	root, _ := newRootCommand(opts)
+	return root
+}
+
+func newRootCommand(opts Options) (*cobra.Command, *cli) {
+	if opts.Stdin == nil {
+		opts.Stdin = os.Stdin
+	}
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+
+	c := &cli{Options: opts, viper: viper.New(), ctx: context.Background()}
+
+	root := &cobra.Command{
+		Use:     "pulsar-ctl",
+		Version: version(),
+		Short:   "Command line tool to manage Pulsar",
+		Long: `Command line tool to manage Pulsar
+
+` + exitCodesHelp,
+
+		// Errors are reported by Execute(), with the usage only for the invalid
+		// command lines
+		SilenceErrors: true,
+		SilenceUsage:  true,
+
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Cobra checks the required flags only after this hook, which
+			// can already send requests, eg: to get the broker version
+			if err := cmd.ValidateRequiredFlags(); err != nil {
+				return err
+			}
+
+			// Reject an unknown output format before sending any request
+			if c.outputFormat != "" {
+				if _, err := printer.New(c.outputFormat); err != nil {
+					return err
+				}
+			}
+
+			c.started = true
+
+			if err := c.loadClientConf(c.configFile); err != nil {
+				return fmt.Errorf("failed to read config file: %s", err)
+			}
+			if err := c.loadContext(c.contextName); err != nil {
+				return err
+			}
+
+			c.ctx = cmd.Context()
+			if timeout := c.viper.GetDuration(timeoutKey); timeout > 0 {
+				c.ctx, c.cancel = context.WithTimeout(c.ctx, timeout)
+			}
+			if err := c.checkVersion(cmd); err != nil {
+				c.release()
+				return err
+			}
+			return nil
+		},
+	}
+
+	root.SetIn(opts.Stdin)
+	root.SetOut(opts.Stdout)
+	root.SetErr(opts.Stderr)
+
+	root.PersistentFlags().StringVar(&c.configFile, "config",
+		"", "config file (default is $PULSAR_CLIENT_CONF or $PULSAR_HOME/conf/client.conf)")
+	root.PersistentFlags().StringVar(&c.contextName, "context",
+		"", "Name of the context to use, instead of the current one")
+
+	flags := root.PersistentFlags()
+	flags.StringVarP(&c.outputFormat, "output", "o", "",
+		"Output format: "+printer.Formats)
+
+	flags.StringP("admin-url", "u",
+		"http://localhost:8080/", "Admin Service URL to which to connect. "+
+			"A comma separated list of URLs can be given, to fail over when a broker is not available")
+	flags.String("url-selection", admin.URLSelectionFailover,
+		"How to pick the admin service URL for each request, when there are many: failover or round-robin")
+
+	flags.String("tls-trust-cert-path", "",
+		"Path to the file with the trusted TLS certificates, in PEM format")
+	flags.String("tls-cert-file", "",
+		"Path to the TLS client certificate file, in PEM format")
+	flags.String("tls-key-file", "",
+		"Path to the TLS client private key file, in PEM format")
+	flags.Bool("tls-allow-insecure", false,
+		"Accept untrusted TLS certificates from the server")
+	flags.Bool("tls-enable-hostname-verification", false,
+		"Verify that the server TLS certificate matches the host name, when it's signed by --tls-trust-cert-path. "+
+			"The certificates signed by the system roots are always verified")
+
+	flags.String("auth-token", "",
+		"Token used to authenticate with the brokers. Can also be set with $PULSAR_AUTH_TOKEN")
+	flags.String("auth-token-file", "",
+		"Path to a file with the token used to authenticate with the brokers")
+
+	flags.String("oauth2-issuer-url", "",
+		"URL of the OAuth2 authorization server. Defaults to the issuer_url of the credentials file")
+	flags.String("oauth2-audience", "",
+		"Audience of the OAuth2 access token, eg: urn:sn:pulsar:my-org:my-instance")
+	flags.String("oauth2-private-key", "",
+		"Path to the OAuth2 credentials file, in JSON with client_id and client_secret")
+	flags.String("oauth2-scope", "",
+		"Scope of the OAuth2 access token")
+
+	flags.String("auth-exec-command", "",
+		`Command that prints the token used to authenticate with the brokers, as JSON: {"token": "...", "expiry": "<RFC 3339 time>"}`)
+	flags.StringArray("auth-exec-arg", nil,
+		"Argument of the auth exec command. Can be repeated")
+
+	flags.String("auth-basic-user", "",
+		"User for the basic authentication. Can also be set with $PULSAR_AUTH_BASIC_USER")
+	flags.String("auth-basic-password", "",
+		"Password for the basic authentication. Can also be set with $PULSAR_AUTH_BASIC_PASSWORD")
+	flags.String("auth-basic-password-file", "",
+		"Path to a file with the password for the basic authentication")
 
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
-	rootCmd.PersistentFlags().StringVar(&configFile, "config",
-		"", "config file (default is $PULSAR_HOME/conf/client.conf)")
+	flags.Int("max-retries", admin.DefaultMaxRetries,
+		"Number of retries of a request after a connection error or a 429, 500, 502, 503 or 504 response")
+	flags.Duration("retry-timeout", admin.DefaultRetryTimeout,
+		"Maximum time spent retrying a request")
+	flags.Bool("retry-post", false,
+		"Retry also the POST requests, which are not idempotent")
 
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	//rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	flags.Bool("dry-run", false,
+		"Print the requests that would modify the state, instead of sending them")
 
-	rootCmd.PersistentFlags().StringVarP(&adminUrl, "admin-url", "u",
-		"http://localhost:8080/","Admin Service URL to which to connect")
+	flags.CountP("verbose", "v",
+		"Trace the HTTP requests on stderr. Repeat to add the headers (-vv) and the bodies (-vvv)")
+	flags.Bool("print-curl", false,
+		"Print on stderr an equivalent curl command for each request")
+
+	flags.Duration("request-timeout", 0,
+		"Maximum time for a single HTTP request, eg: 30s. Zero means no timeout")
+	flags.Duration("timeout", 0,
+		"Maximum time for the whole command, including retries, eg: 5m. Zero means no timeout")
+
+	c.viper.BindPFlag(webServiceUrlKey, flags.Lookup("admin-url"))
+	c.viper.BindPFlag(urlSelectionKey, flags.Lookup("url-selection"))
+	c.viper.BindPFlag(tlsTrustCertsFilePathKey, flags.Lookup("tls-trust-cert-path"))
+	c.viper.BindPFlag(tlsCertificateFilePathKey, flags.Lookup("tls-cert-file"))
+	c.viper.BindPFlag(tlsKeyFilePathKey, flags.Lookup("tls-key-file"))
+	c.viper.BindPFlag(tlsAllowInsecureConnectionKey, flags.Lookup("tls-allow-insecure"))
+	c.viper.BindPFlag(tlsEnableHostnameVerificationKey, flags.Lookup("tls-enable-hostname-verification"))
+	c.viper.BindPFlag(authTokenKey, flags.Lookup("auth-token"))
+	c.viper.BindPFlag(authTokenFileKey, flags.Lookup("auth-token-file"))
+	c.viper.BindPFlag(oauth2IssuerUrlKey, flags.Lookup("oauth2-issuer-url"))
+	c.viper.BindPFlag(oauth2AudienceKey, flags.Lookup("oauth2-audience"))
+	c.viper.BindPFlag(oauth2PrivateKeyKey, flags.Lookup("oauth2-private-key"))
+	c.viper.BindPFlag(oauth2ScopeKey, flags.Lookup("oauth2-scope"))
+	c.viper.BindPFlag(authExecCommandKey, flags.Lookup("auth-exec-command"))
+	c.viper.BindPFlag(authExecArgsKey, flags.Lookup("auth-exec-arg"))
+	c.viper.BindPFlag(basicAuthUserKey, flags.Lookup("auth-basic-user"))
+	c.viper.BindPFlag(basicAuthPasswordKey, flags.Lookup("auth-basic-password"))
+	c.viper.BindPFlag(basicAuthPasswordFileKey, flags.Lookup("auth-basic-password-file"))
+	c.viper.BindPFlag(maxRetriesKey, flags.Lookup("max-retries"))
+	c.viper.BindPFlag(retryTimeoutKey, flags.Lookup("retry-timeout"))
+	c.viper.BindPFlag(retryPostKey, flags.Lookup("retry-post"))
+	c.viper.BindPFlag(dryRunKey, flags.Lookup("dry-run"))
+	c.viper.BindPFlag(verboseKey, flags.Lookup("verbose"))
+	c.viper.BindPFlag(printCurlKey, flags.Lookup("print-curl"))
+	c.viper.BindPFlag(requestTimeoutKey, flags.Lookup("request-timeout"))
+	c.viper.BindPFlag(timeoutKey, flags.Lookup("timeout"))
+
+	c.viper.BindEnv(authTokenKey, "PULSAR_AUTH_TOKEN")
+	c.viper.BindEnv(basicAuthUserKey, "PULSAR_AUTH_BASIC_USER")
+	c.viper.BindEnv(basicAuthPasswordKey, "PULSAR_AUTH_BASIC_PASSWORD")
+
+	// The other settings are read from the environment variables with the
+	// PULSAR_CTL_ prefix, eg: PULSAR_CTL_TIMEOUT, so that common names like
+	// TIMEOUT or VERBOSE don't change the behavior
+	c.viper.SetEnvPrefix("PULSAR_CTL")
+	c.viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_", ".", "_"))
+	c.viper.AutomaticEnv()
+
+	c.flags = flags
+
+	root.AddCommand(newApiCommand(c))
+	root.AddCommand(newClustersCommand(c))
+	root.AddCommand(newNamespacesCommand(c))
+	root.AddCommand(newTenantsCommand(c))
+	root.AddCommand(newTopicsCommand(c))
+	root.AddCommand(newConfigCommand(c))
+
+	c.releaseAfterRun(root)
+	return root, c
 }
 
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	if configFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(configFile)
-	} else {
-		// Find home directory.
-		home, err := homedir.Dir()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+// releaseAfterRun wraps the commands of the tree, so that the timer of the
+// --timeout context is released when they return, whatever the result and
+// also when the tree is run with Execute()
+func (c *cli) releaseAfterRun(cmd *cobra.Command) {
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			defer c.release()
+			return run(cmd, args)
 		}
+	}
+
+	for _, child := range cmd.Commands() {
+		c.releaseAfterRun(child)
+	}
+}
 
-		// Search config in home directory with name ".pulsar-cli" (without extension).
-		viper.AddConfigPath(home)
-		viper.SetConfigName(".pulsar-cli")
+// release cancels the context of the running command, if it has a timeout
+func (c *cli) release() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
 	}
+}
 
-	viper.AutomaticEnv() // read in environment variables that match
+// Execute runs the command line of the process, reporting the failures with
+// the exit code. This is called by main.main().
+func Execute() {
+	ctx, stop := signalContext()
+	root, c := newRootCommand(Options{})
+	code := c.execute(ctx, root)
+	stop()
+	os.Exit(code)
+}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+// execute runs the command tree and reports the failure, if any, on stderr.
+// It returns the exit code.
+func (c *cli) execute(ctx context.Context, root *cobra.Command) int {
+	cmd, err := root.ExecuteContextC(ctx)
+	c.release()
+	if err == nil {
+		return ExitOK
+	}
+
+	code := c.exitCode(err)
+	c.printError(err, code)
+	if code == ExitUsage {
+		fmt.Fprintf(c.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+	return code
+}
+
+// clientConfig builds the admin client configuration from the flags and the
+// config file, with the flags taking precedence
+func (c *cli) clientConfig() (*admin.Config, error) {
+	config := &admin.Config{
+		WebServiceUrl:                 c.viper.GetString(webServiceUrlKey),
+		URLSelection:                  c.viper.GetString(urlSelectionKey),
+		TLSTrustCertsFilePath:         c.viper.GetString(tlsTrustCertsFilePathKey),
+		TLSCertFile:                   c.viper.GetString(tlsCertificateFilePathKey),
+		TLSKeyFile:                    c.viper.GetString(tlsKeyFilePathKey),
+		TLSAllowInsecureConnection:    c.viper.GetBool(tlsAllowInsecureConnectionKey),
+		TLSEnableHostnameVerification: c.viper.GetBool(tlsEnableHostnameVerificationKey),
+		AuthToken:                     c.viper.GetString(authTokenKey),
+		AuthTokenFile:                 c.viper.GetString(authTokenFileKey),
+		BasicAuthUser:                 c.viper.GetString(basicAuthUserKey),
+		BasicAuthPassword:             c.viper.GetString(basicAuthPasswordKey),
+		BasicAuthPasswordFile:         c.viper.GetString(basicAuthPasswordFileKey),
+		MaxRetries:                    c.viper.GetInt(maxRetriesKey),
+		RetryPost:                     c.viper.GetBool(retryPostKey),
+		RetryTimeout:                  c.viper.GetDuration(retryTimeoutKey),
+		DryRun:                        c.viper.GetBool(dryRunKey),
+		DryRunOutput:                  c.Stdout,
+		Verbosity:                     c.viper.GetInt(verboseKey),
+		PrintCurl:                     c.viper.GetBool(printCurlKey),
+		TraceOutput:                   c.Stderr,
+		RequestTimeout:                c.viper.GetDuration(requestTimeoutKey),
+		UserAgent:                     "pulsar-ctl " + version(),
+	}
+
+	if c.viper.GetString(oauth2PrivateKeyKey) != "" {
+		config.OAuth2 = oauth2Config(c.viper.GetString(oauth2IssuerUrlKey), c.viper.GetString(oauth2AudienceKey),
+			c.viper.GetString(oauth2PrivateKeyKey), c.viper.GetString(oauth2ScopeKey))
+	}
+
+	if c.viper.GetString(authExecCommandKey) != "" {
+		config.Exec = &admin.ExecConfig{
+			Command:  c.viper.GetString(authExecCommandKey),
+			Args:     c.viper.GetStringSlice(authExecArgsKey),
+			CacheDir: credentialsCacheDir("exec"),
+		}
+	}
+
+	// An explicit token file, OAuth2 configuration, credential command or
+	// basic authentication takes precedence over the token from
+	// $PULSAR_AUTH_TOKEN
+	explicit := config.AuthTokenFile != "" || config.OAuth2 != nil || config.Exec != nil || config.BasicAuthUser != ""
+	if explicit && !c.flags.Changed("auth-token") {
+		config.AuthToken = ""
+	}
+
+	// Credentials given through flags or environment take precedence over the
+	// authentication plugin from the config file
+	if config.AuthToken == "" && !explicit {
+		err := applyAuthPlugin(config, c.viper.GetString(authPluginKey), c.viper.GetString(authParamsKey))
+		if err != nil {
+			return nil, err
+		}
 	}
+	return config, nil
 }
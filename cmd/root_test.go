@@ -0,0 +1,112 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+	"github.com/merlimat/pulsar-ctl/testing/fakeadmin"
+)
+
+func TestNewRootCommandWithClient(t *testing.T) {
+	t.Parallel()
+
+	server := fakeadmin.NewServer()
+	defer server.Close()
+	server.AddCluster("us-west", admin.ClusterData{ServiceUrl: "http://us-west:8080"})
+
+	client, err := admin.New(&admin.Config{WebServiceUrl: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	root := NewRootCommand(Options{Stdout: &stdout, Client: client})
+	root.SetArgs([]string{"--admin-url", "http://unreachable:1", "clusters", "list"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "us-west\n" {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+
+	root = NewRootCommand(Options{Stdout: &stdout, Client: client})
+	root.SetArgs([]string{"clusters", "get", "us-east"})
+	if err := root.Execute(); !admin.IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestTimeoutReleased(t *testing.T) {
+	t.Parallel()
+
+	server := fakeadmin.NewServer()
+	defer server.Close()
+
+	// The timer of the timeout is released without going through execute(),
+	// whether the command succeeds or fails
+	for _, args := range [][]string{{"clusters", "list"}, {"clusters", "get", "missing"}} {
+		root, c := newRootCommand(Options{Stdout: ioutil.Discard, ContextsPath: filepath.Join(t.TempDir(), "config")})
+		root.SetArgs(append([]string{"--admin-url", server.URL, "--timeout", "1h"}, args...))
+		root.Execute()
+
+		if c.ctx.Err() != context.Canceled {
+			t.Errorf("%v: the context is not released: %v", args, c.ctx.Err())
+		}
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	c := newCommandTest(t)
+
+	// Only the variables with the PULSAR_CTL_ prefix are read
+	t.Setenv("TIMEOUT", "1ns")
+	t.Setenv("VERBOSE", "2")
+	t.Setenv("DRYRUN", "true")
+	c.run("clusters", "create", "us-west", "--service-url", "http://us-west:8080")
+	c.run("clusters", "list")
+
+	t.Setenv("PULSAR_CTL_DRYRUN", "true")
+	c.run("clusters", "delete", "us-west")
+	c.run("clusters", "list")
+	c.check()
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.SetBlocking(true)
+
+	c.run("clusters", "list", "--timeout", "100ms")
+	c.run("clusters", "list", "--request-timeout", "100ms", "--max-retries", "0")
+	c.check()
+}
+
+func TestInterrupted(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.SetBlocking(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	c.runWithContext(ctx, "clusters", "list")
+	c.check()
+}
@@ -0,0 +1,85 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// signalContext returns a context cancelled on SIGINT/SIGTERM, to stop the
+// running command. A second signal kills the process, in case the command
+// doesn't stop promptly.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+
+	return ctx, cancel
+}
+
+// incompleteError reports an operation on multiple resources that stopped
+// before processing all of them
+type incompleteError struct {
+	completed []string
+	pending   []string
+	err       error
+}
+
+func (e *incompleteError) Error() string {
+	completed := "none"
+	if len(e.completed) > 0 {
+		completed = strings.Join(e.completed, ", ")
+	}
+	return fmt.Sprintf("%s (completed: %s; not completed: %s)",
+		e.err, completed, strings.Join(e.pending, ", "))
+}
+
+func (e *incompleteError) Unwrap() error {
+	return e.err
+}
+
+// forEachName runs the operation on each of the names in sequence. It stops
+// at the first failure, or when the command is cancelled, reporting which
+// names were completed.
+func forEachName(ctx context.Context, names []string, operation func(name string) error) error {
+	for i, name := range names {
+		err := ctx.Err()
+		if err == nil {
+			err = operation(name)
+		}
+
+		if err != nil {
+			if len(names) == 1 {
+				return err
+			}
+			return &incompleteError{completed: names[:i], pending: names[i:], err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package cmd
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSignalContext(t *testing.T) {
+	ctx, stop := signalContext()
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the context is not cancelled by SIGINT")
+	}
+}
+
+func TestSignalContextStopped(t *testing.T) {
+	ctx, stop := signalContext()
+	stop()
+
+	if ctx.Err() == nil {
+		t.Error("the context is not cancelled by stop")
+	}
+}
@@ -15,23 +15,16 @@
 package cmd
 
 import (
+	"github.com/merlimat/pulsar-ctl/admin"
 	"github.com/spf13/cobra"
-	"fmt"
-	"encoding/json"
 )
 
-var tenantBasePath = "/admin/v2/tenants"
-
-type TenantInfo struct {
-	AdminRoles      []string `json:"adminRoles"`
-	AllowedClusters []string `json:"allowedClusters"`
-}
-
 // tenantsCmd represents the tenants command
-var tenantsCmd = &cobra.Command{
-	Use:   "tenants",
-	Short: "Operations about Pulsar's tenants",
-	Long: `Manage tenants
+func newTenantsCommand(c *cli) *cobra.Command {
+	tenantsCmd := &cobra.Command{
+		Use:   "tenants",
+		Short: "Operations about Pulsar's tenants",
+		Long: `Manage tenants
 
 For example creating a tenant:
 
@@ -41,9 +34,17 @@ Update tenant's configuration:
 
     pulsar-ctl tenants update my-tenant --allowed-clusters us-west,us-east
 `,
+	}
+
+	tenantsCmd.AddCommand(tenantsList(c))
+	tenantsCmd.AddCommand(tenantsGet(c))
+	tenantsCmd.AddCommand(tenantsCreate(c))
+	tenantsCmd.AddCommand(tenantsUpdate(c))
+	tenantsCmd.AddCommand(tenantsDelete(c))
+	return tenantsCmd
 }
 
-func tenantsList() {
+func tenantsList(c *cli) *cobra.Command {
 	var adminRoles []string
 	var clusters []string
 
@@ -54,8 +55,17 @@ func tenantsList() {
 		Example: "pulsar-ctl tenants list",
 		Args:    cobra.ExactArgs(0),
 
-		Run: func(cmd *cobra.Command, args []string) {
-			RestPrintStringList(tenantBasePath)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			tenants, err := client.Tenants().List(c.ctx)
+			if err != nil {
+				return err
+			}
+			return c.printNames(tenants)
 		},
 	}
 
@@ -64,10 +74,10 @@ func tenantsList() {
 	createCmd.Flags().StringSliceVarP(&clusters, "clusters", "c", nil,
 		"Comma separated allowed clusters. If empty, the tenant will have access to all clusters")
 
-	tenantsCmd.AddCommand(createCmd);
+	return createCmd
 }
 
-func tenantsGet() {
+func tenantsGet(c *cli) *cobra.Command {
 	var getCmd = &cobra.Command{
 		Use:   "get",
 		Short: "Get information regarding a tenant",
@@ -75,19 +85,24 @@ func tenantsGet() {
 		Example: "pulsar-ctl tenants get my-tenant",
 		Args:    cobra.ExactArgs(1),
 
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println(RestGet(tenantPath(args[0])))
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			tenant, err := client.Tenants().Get(c.ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return c.printObject(&tenantObject{name: args[0], tenant: tenant}, "json")
 		},
 	}
 
-	tenantsCmd.AddCommand(getCmd);
+	return getCmd
 }
 
-func tenantPath(name string) string {
-	return tenantBasePath + "/" + name
-}
-
-func tenantsCreate() {
+func tenantsCreate(c *cli) *cobra.Command {
 	var adminRoles []string
 	var clusters []string
 
@@ -98,17 +113,22 @@ func tenantsCreate() {
 		Example: "pulsar-ctl tenants create my-tenant",
 		Args:    cobra.ExactArgs(1),
 
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
 			// By default, if no clusters are provided, allow the tenant to use all clusters
 			if len(clusters) == 0 {
-				clusters = GetClustersList()
+				clusters, err = GetClustersList(c.ctx, client)
+				if err != nil {
+					return err
+				}
 			}
 
-			fmt.Println("clusters: ", clusters)
-			var tenant = TenantInfo{AdminRoles: adminRoles, AllowedClusters: clusters}
-
-			fmt.Println("tenant: ", tenant)
-			RestPut(tenantBasePath + "/" + args[0], tenant)
+			var tenant = admin.TenantInfo{AdminRoles: adminRoles, AllowedClusters: clusters}
+			return client.Tenants().Create(c.ctx, args[0], tenant)
 		},
 	}
 
@@ -117,10 +137,10 @@ func tenantsCreate() {
 	createCmd.Flags().StringSliceVarP(&clusters, "clusters", "c", nil,
 		"Comma separated allowed clusters. If empty, the tenant will have access to all clusters")
 
-	tenantsCmd.AddCommand(createCmd);
+	return createCmd
 }
 
-func tenantsUpdate() {
+func tenantsUpdate(c *cli) *cobra.Command {
 	var adminRoles []string
 	var clusters []string
 
@@ -131,11 +151,17 @@ func tenantsUpdate() {
 		Example: "pulsar-ctl tenants update my-tenant --allowed-clusters us-west,us-east",
 		Args:    cobra.ExactArgs(1),
 
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// By default, if no clusters are provided, allow the tenant to use all clusters
 
-			var tenant = TenantInfo{}
-			json.Unmarshal([]byte(RestGet(tenantBasePath + "/" + args[0])), &tenant)
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			tenant, err := client.Tenants().Get(c.ctx, args[0])
+			if err != nil {
+				return err
+			}
 
 			if len(clusters) != 0 {
 				tenant.AllowedClusters = clusters
@@ -145,7 +171,7 @@ func tenantsUpdate() {
 				tenant.AdminRoles = adminRoles
 			}
 
-			RestPost(tenantPath(args[0]), tenant)
+			return client.Tenants().Update(c.ctx, args[0], tenant)
 		},
 	}
 
@@ -154,31 +180,27 @@ func tenantsUpdate() {
 	updateCmd.Flags().StringSliceVarP(&clusters, "clusters", "c", nil,
 		"Comma separated allowed clusters. If omitted, the current set of clusters will be preserved")
 
-	tenantsCmd.AddCommand(updateCmd);
+	return updateCmd
 }
 
-func tenantsDelete() {
+func tenantsDelete(c *cli) *cobra.Command {
 	var deleteCmd = &cobra.Command{
 		Use:   "delete",
-		Short: "Delete a tenant",
+		Short: "Delete one or more tenants",
 		// Long: `Manage tenants`,
 		Example: "pulsar-ctl tenants delete my-tenant",
-		Args:    cobra.ExactArgs(1),
+		Args:    cobra.MinimumNArgs(1),
 
-		Run: func(cmd *cobra.Command, args []string) {
-			RestDelete(tenantPath(args[0]))
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return forEachName(c.ctx, args, func(name string) error {
+				return client.Tenants().Delete(c.ctx, name)
+			})
 		},
 	}
 
-	tenantsCmd.AddCommand(deleteCmd);
-}
-
-func init() {
-	tenantsList()
-	tenantsGet()
-	tenantsCreate()
-	tenantsUpdate()
-	tenantsDelete()
-
-	rootCmd.AddCommand(tenantsCmd)
+	return deleteCmd
 }
@@ -0,0 +1,43 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+func TestTenants(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddCluster("us-west", admin.ClusterData{ServiceUrl: "http://us-west:8080"})
+	c.server.AddCluster("us-east", admin.ClusterData{ServiceUrl: "http://us-east:8080"})
+	c.server.AddTenant("public", admin.TenantInfo{AllowedClusters: []string{"us-west"}})
+	c.server.AddNamespace("public/default")
+
+	c.run("tenants", "list")
+	c.run("tenants", "create", "my-tenant", "--admin-roles", "admin")
+	c.run("tenants", "create", "my-tenant")
+	c.run("tenants", "create", "other-tenant", "--clusters", "us-north")
+	c.run("tenants", "get", "my-tenant")
+	c.run("tenants", "update", "my-tenant", "--clusters", "us-west")
+	c.run("tenants", "get", "my-tenant", "-o", "table")
+	c.run("tenants", "update", "missing", "--clusters", "us-west")
+	c.run("tenants", "delete", "my-tenant", "public")
+	c.run("tenants", "list")
+	c.run("clusters", "delete", "us-west")
+	c.check()
+}
@@ -15,44 +15,158 @@
 package cmd
 
 import (
+	"github.com/merlimat/pulsar-ctl/names"
 	"github.com/spf13/cobra"
 )
 
-// tenantsCmd represents the tenants command
-var topicsCmd = &cobra.Command{
-	Use:   "topics",
-	Short: "Operations about Pulsar's topics",
-	Long: `Manage topics
+// newTopicsCommand builds the topics command and its subcommands
+func newTopicsCommand(c *cli) *cobra.Command {
+	topicsCmd := &cobra.Command{
+		Use:   "topics",
+		Short: "Operations about Pulsar's topics",
+		Long: `Manage topics
 
-For example, getting the stats for a topic:
+For example, listing the topics of a namespace:
 
-    pulsar-ctl topics stats my-topic
+    pulsar-ctl topics list my-tenant/my-namespace
 
-Delete a topic
+Allow a role to produce on a topic:
 
-    pulsar-ctl topics delete my-topic
+    pulsar-ctl topics grant-permission my-topic --role my-app --actions produce
 `,
+	}
+
+	topicsCmd.AddCommand(topicsList(c))
+	topicsCmd.AddCommand(topicsPermissions(c))
+	topicsCmd.AddCommand(topicsGrantPermission(c))
+	topicsCmd.AddCommand(topicsRevokePermission(c))
+	return topicsCmd
 }
 
-func topicsList() {
+func topicsList(c *cli) *cobra.Command {
 	var listCmd = &cobra.Command{
 		Use:   "list",
 		Short: "Get the list of topics under a namespace",
 		// Long: `Manage tenants`,
-		Example: "pulsar-ctl topics list my-tenant/my-namespace",
+		Example: `pulsar-ctl topics list my-tenant/my-namespace
+pulsar-ctl topics list my-tenant/my-cluster/my-namespace`,
+		Args: cobra.MaximumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := c.namespaceArg(args)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			topics, err := client.Topics().List(c.ctx, namespace)
+			if err != nil {
+				return err
+			}
+			return c.printNames(topics)
+		},
+	}
+
+	return listCmd
+}
+
+func topicsPermissions(c *cli) *cobra.Command {
+	var permissionsCmd = &cobra.Command{
+		Use:     "permissions",
+		Short:   "Get the permissions of the roles on a topic, including the ones granted on its namespace",
+		Example: "pulsar-ctl topics permissions persistent://my-tenant/my-namespace/my-topic",
+		Args:    cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topic, err := names.TopicNameParse(args[0])
+			if err != nil {
+				return newUsageError(err)
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+
+			permissions, err := client.Topics().Permissions(c.ctx, topic)
+			if err != nil {
+				return err
+			}
+			return c.printObject(permissionsObject(permissions), "json")
+		},
+	}
+
+	return permissionsCmd
+}
+
+func topicsGrantPermission(c *cli) *cobra.Command {
+	var role string
+	var actions []string
+
+	var grantCmd = &cobra.Command{
+		Use:     "grant-permission",
+		Short:   "Allow actions to a role on a topic",
+		Example: "pulsar-ctl topics grant-permission persistent://my-tenant/my-namespace/my-topic --role my-app --actions consume",
 		Args:    cobra.ExactArgs(1),
 
-		Run: func(cmd *cobra.Command, args []string) {
-			RestPrintStringList()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topic, err := names.TopicNameParse(args[0])
+			if err != nil {
+				return newUsageError(err)
+			}
+			if err := validateActions(actions); err != nil {
+				return err
+			}
+			if err := c.checkActionsVersion(actions); err != nil {
+				return err
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.Topics().GrantPermission(c.ctx, topic, role, actions)
 		},
 	}
 
-	topicsCmd.AddCommand(listCmd);
+	grantCmd.Flags().StringVar(&role, "role", "",
+		"Role to grant the permission to, eg: the subject of its token")
+	grantCmd.Flags().StringSliceVar(&actions, "actions", nil, actionsHelp)
+
+	grantCmd.MarkFlagRequired("role")
+	grantCmd.MarkFlagRequired("actions")
+	return grantCmd
 }
 
+func topicsRevokePermission(c *cli) *cobra.Command {
+	var role string
+
+	var revokeCmd = &cobra.Command{
+		Use:     "revoke-permission",
+		Short:   "Revoke the permissions of a role on a topic",
+		Example: "pulsar-ctl topics revoke-permission persistent://my-tenant/my-namespace/my-topic --role my-app",
+		Args:    cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			topic, err := names.TopicNameParse(args[0])
+			if err != nil {
+				return newUsageError(err)
+			}
+
+			client, err := c.adminClient()
+			if err != nil {
+				return err
+			}
+			return client.Topics().RevokePermission(c.ctx, topic, role)
+		},
+	}
 
-func init() {
-	topicsList()
+	revokeCmd.Flags().StringVar(&role, "role", "", "Role to revoke the permissions of")
 
-	rootCmd.AddCommand(topicsCmd)
+	revokeCmd.MarkFlagRequired("role")
+	return revokeCmd
 }
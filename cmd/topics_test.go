@@ -0,0 +1,57 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+func TestTopics(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddTenant("public", admin.TenantInfo{})
+	c.server.AddNamespace("public/default")
+	c.server.AddNamespace("public/empty")
+	c.server.AddTopic("persistent://public/default/orders")
+	c.server.AddTopic("persistent://public/default/payments")
+	c.server.AddTopic("non-persistent://public/default/clicks")
+
+	c.run("topics", "list", "public/default")
+	c.run("topics", "list", "public/default", "-o", "json")
+	c.run("topics", "list", "public/empty")
+	c.run("topics", "list", "public/missing")
+	c.run("topics", "list", "public/default/orders/old")
+	c.run("topics", "list", "public/def@ult")
+	c.run("topics", "list")
+	c.run("config", "set-context", "local", "--tenant", "public", "--namespace", "default")
+	c.run("config", "use-context", "local")
+	c.run("topics", "list")
+	c.check()
+}
+
+func TestTopicsV1(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddTenant("sample", admin.TenantInfo{})
+	c.server.AddNamespace("sample/standalone/ns1")
+	c.server.AddTopic("persistent://sample/standalone/ns1/orders")
+	c.server.AddTopic("persistent://sample/standalone/ns2/orders")
+
+	c.run("topics", "list", "sample/standalone/ns1")
+	c.run("topics", "list", "sample/standalone/missing")
+	c.check()
+}
@@ -0,0 +1,88 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Multipliers of the size suffixes, in powers of 1024 like the Pulsar tools
+var sizeUnits = map[byte]int64{
+	'k': 1 << 10,
+	'm': 1 << 20,
+	'g': 1 << 30,
+	't': 1 << 40,
+}
+
+// Multipliers of the time suffixes, in seconds
+var timeUnits = map[byte]int64{
+	's': 1,
+	'm': 60,
+	'h': 60 * 60,
+	'd': 24 * 60 * 60,
+	'w': 7 * 24 * 60 * 60,
+}
+
+// ParseSize parses a size in bytes, with an optional k, m, g or t suffix, eg:
+// 10G. -1 is returned as it is, to mean no limit.
+func ParseSize(size string) (int64, error) {
+	n, err := parseWithUnit(size, sizeUnits)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size '%s', it should be a number with an optional unit: k, m, g or t", size)
+	}
+	return n, nil
+}
+
+// ParseRelativeTime parses a duration in seconds, with an optional s, m, h, d
+// or w suffix, eg: 7d. -1 is returned as it is, to mean no limit.
+func ParseRelativeTime(duration string) (int64, error) {
+	n, err := parseWithUnit(duration, timeUnits)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s', it should be a number with an optional unit: s, m, h, d or w", duration)
+	}
+	return n, nil
+}
+
+func parseWithUnit(value string, units map[byte]int64) (int64, error) {
+	if value == "-1" {
+		return -1, nil
+	}
+
+	multiplier := int64(1)
+	number := strings.ToLower(value)
+	if len(number) > 0 {
+		if unit, ok := units[number[len(number)-1]]; ok {
+			multiplier = unit
+			number = number[:len(number)-1]
+		}
+	}
+
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 || n > (1<<63-1)/multiplier {
+		return 0, fmt.Errorf("invalid value '%s'", value)
+	}
+	return n * multiplier, nil
+}
+
+// FormatSize formats a size in bytes with the largest exact unit, eg: 10G
+func FormatSize(size int64) string {
+	return strings.ToUpper(formatWithUnit(size, sizeUnits, "tgmk"))
+}
+
+// FormatRelativeTime formats a duration in seconds with the largest exact
+// unit, eg: 7d
+func FormatRelativeTime(duration int64) string {
+	return formatWithUnit(duration, timeUnits, "wdhm")
+}
+
+func formatWithUnit(value int64, units map[byte]int64, order string) string {
+	if value < 0 {
+		return "-1"
+	}
+	for i := 0; i < len(order); i++ {
+		if unit := units[order[i]]; value > 0 && value%unit == 0 {
+			return strconv.FormatInt(value/unit, 10) + order[i:i+1]
+		}
+	}
+	return strconv.FormatInt(value, 10)
+}
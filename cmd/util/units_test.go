@@ -0,0 +1,64 @@
+package util
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	for size, expected := range map[string]int64{
+		"0":    0,
+		"-1":   -1,
+		"1024": 1024,
+		"10k":  10 << 10,
+		"10M":  10 << 20,
+		"10G":  10 << 30,
+		"2t":   2 << 40,
+	} {
+		if actual, err := ParseSize(size); err != nil || actual != expected {
+			t.Errorf("ParseSize(%s) = %d, %v, expected %d", size, actual, err, expected)
+		}
+	}
+
+	for _, size := range []string{"", "G", "-2", "1.5G", "10x", "10000000000t"} {
+		if _, err := ParseSize(size); err == nil {
+			t.Errorf("ParseSize(%s): expected an error", size)
+		}
+	}
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	for duration, expected := range map[string]int64{
+		"-1":  -1,
+		"30":  30,
+		"30s": 30,
+		"5m":  5 * 60,
+		"2h":  2 * 60 * 60,
+		"7d":  7 * 24 * 60 * 60,
+		"1W":  7 * 24 * 60 * 60,
+	} {
+		if actual, err := ParseRelativeTime(duration); err != nil || actual != expected {
+			t.Errorf("ParseRelativeTime(%s) = %d, %v, expected %d", duration, actual, err, expected)
+		}
+	}
+
+	for _, duration := range []string{"", "d", "1y", "-5m"} {
+		if _, err := ParseRelativeTime(duration); err == nil {
+			t.Errorf("ParseRelativeTime(%s): expected an error", duration)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, test := range []struct{ actual, expected string }{
+		{FormatSize(10 << 30), "10G"},
+		{FormatSize(1536 << 20), "1536M"},
+		{FormatSize(1000), "1000"},
+		{FormatSize(-1), "-1"},
+		{FormatRelativeTime(7 * 24 * 60 * 60), "1w"},
+		{FormatRelativeTime(36 * 60 * 60), "36h"},
+		{FormatRelativeTime(90), "90"},
+		{FormatRelativeTime(0), "0"},
+	} {
+		if test.actual != test.expected {
+			t.Errorf("got %s, expected %s", test.actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+	"github.com/spf13/cobra"
+)
+
+// Version of pulsar-ctl, set when building a release with
+// -ldflags "-X github.com/merlimat/pulsar-ctl/cmd.Version=2.1.0". When it's
+// not set, the version of the module is taken from the build info, eg: when
+// installed with go install.
+var Version = ""
+
+// version returns the version of pulsar-ctl, or "dev" when it's unknown, eg:
+// when built from a working copy
+func version() string {
+	if Version != "" {
+		return Version
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return strings.TrimPrefix(info.Main.Version, "v")
+	}
+	return "dev"
+}
+
+// Annotation of the commands that need a minimum version of Pulsar, eg:
+// 2.2.0. The requirement of a command group applies to all its subcommands.
+const minVersionAnnotation = "pulsar-ctl/min-pulsar-version"
+
+// The version of the brokers of a context is detected again after this time,
+// to notice the upgrades
+const brokerVersionTTL = time.Hour
+
+// minVersion returns the highest version of Pulsar required by the command
+// and its parents, or "" if there is no requirement
+func minVersion(cmd *cobra.Command) string {
+	required := ""
+	for ; cmd != nil; cmd = cmd.Parent() {
+		version := cmd.Annotations[minVersionAnnotation]
+		if version != "" && (required == "" || compareVersions(version, required) > 0) {
+			required = version
+		}
+	}
+	return required
+}
+
+// checkVersion fails if the cluster runs a version of Pulsar older than the
+// one required by the command, instead of letting the broker answer with an
+// obscure 404 or 405
+func (c *cli) checkVersion(cmd *cobra.Command) error {
+	required := minVersion(cmd)
+	if required == "" {
+		return nil
+	}
+	return c.requireVersion("'"+cmd.CommandPath()+"'", required)
+}
+
+// requireVersion fails if the cluster runs a version of Pulsar older than the
+// one required by a feature, eg: a command or a flag value. The brokers that
+// predate the version endpoint cannot be checked, so the request is sent
+// and they reject it themselves if needed.
+func (c *cli) requireVersion(feature string, required string) error {
+	version, err := c.brokerVersion()
+	if err != nil || version == "" {
+		return err
+	}
+
+	if compareVersions(version, required) < 0 {
+		return fmt.Errorf("%s requires Pulsar >= %s, cluster runs %s", feature, required, version)
+	}
+	return nil
+}
+
+// cachedVersion is the version of the brokers, with the admin URL it was
+// detected from
+type cachedVersion struct {
+	AdminUrl string    `json:"admin-url"`
+	Version  string    `json:"version"`
+	Expiry   time.Time `json:"expiry"`
+}
+
+// brokerVersion returns the version of Pulsar run by the brokers, or "" if
+// they are older than the version endpoint. It is cached for the context in
+// use, or for the admin URL without context, so that it's only detected once
+// in a while and not by every command.
+func (c *cli) brokerVersion() (string, error) {
+	adminUrl := c.viper.GetString(webServiceUrlKey)
+	cachePath := c.versionCachePath(adminUrl)
+
+	if data, err := ioutil.ReadFile(cachePath); err == nil {
+		cached := cachedVersion{}
+		if json.Unmarshal(data, &cached) == nil && cached.AdminUrl == adminUrl && time.Now().Before(cached.Expiry) {
+			return cached.Version, nil
+		}
+	}
+
+	client, err := c.adminClient()
+	if err != nil {
+		return "", err
+	}
+
+	version, err := client.Brokers().Version(c.ctx)
+	if status := admin.StatusCode(err); status == http.StatusNotFound || status == http.StatusMethodNotAllowed {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	// Failing to cache the version only means it's detected again next time
+	if cachePath != "" {
+		data, _ := json.Marshal(cachedVersion{AdminUrl: adminUrl, Version: version, Expiry: time.Now().Add(brokerVersionTTL)})
+		if os.MkdirAll(filepath.Dir(cachePath), 0700) == nil {
+			ioutil.WriteFile(cachePath, data, 0600)
+		}
+	}
+	return version, nil
+}
+
+// versionCachePath returns the file where the broker version is cached, next
+// to the contexts file: one per context, or per admin URL without context
+func (c *cli) versionCachePath(adminUrl string) string {
+	path, err := c.contextsPath()
+	if err != nil {
+		return ""
+	}
+
+	name := c.context
+	if name == "" {
+		sum := sha256.Sum256([]byte(adminUrl))
+		name = "url-" + hex.EncodeToString(sum[:16])
+	}
+	return filepath.Join(filepath.Dir(path), "cache", "versions", name+".json")
+}
+
+// compareVersions compares two Pulsar versions, eg: 2.10.2 and 2.9.0, by their
+// numeric components. The suffixes like -SNAPSHOT are ignored.
+func compareVersions(a string, b string) int {
+	va, vb := versionNumbers(a), versionNumbers(b)
+	for i := 0; i < len(va) || i < len(vb); i++ {
+		var na, nb int
+		if i < len(va) {
+			na = va[i]
+		}
+		if i < len(vb) {
+			nb = vb[i]
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionNumbers(version string) []int {
+	numbers := []int{}
+	for _, part := range strings.Split(version, ".") {
+		digits := part
+		if end := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+			digits = part[:end]
+		}
+
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+
+		// The rest is a suffix, eg: 0-SNAPSHOT
+		if len(digits) < len(part) {
+			break
+		}
+	}
+	return numbers
+}
@@ -0,0 +1,113 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+func TestVersion(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddTenant("public", admin.TenantInfo{})
+	c.server.AddNamespace("public/default")
+	c.server.SetVersion("1.22.1-incubating")
+
+	c.run("--version")
+	c.run("namespaces", "list", "public")
+	c.run("tenants", "list")
+	c.run("clusters", "list")
+
+	// The flags are checked before the version
+	c.run("namespaces", "list", "public", "--output", "xml")
+
+	// The version is cached for the admin URL
+	c.server.SetVersion("2.10.0")
+	c.run("namespaces", "list", "public")
+
+	// And for the context
+	c.run("config", "set-context", "local", "--admin-url", c.server.URL)
+	c.run("config", "use-context", "local")
+	c.run("namespaces", "list", "public")
+	c.server.SetVersion("1.22.1-incubating")
+	c.run("namespaces", "list", "public")
+	c.check()
+}
+
+func TestVersionLegacy(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddTenant("sample", admin.TenantInfo{AllowedClusters: []string{"standalone"}})
+	c.server.SetVersion("1.22.1-incubating")
+
+	// The legacy namespaces are served by the 1.x brokers
+	c.run("namespaces", "create", "sample/standalone/ns1")
+	c.run("namespaces", "set-retention", "sample/standalone/ns1", "--time", "1h", "--size", "1G")
+	c.run("namespaces", "get-retention", "sample/standalone/ns1")
+	c.run("namespaces", "grant-permission", "sample/standalone/ns1", "--role", "app", "--actions", "produce")
+	c.run("namespaces", "permissions", "sample/standalone/ns1")
+	c.run("namespaces", "delete", "sample/standalone/ns1")
+	c.check()
+}
+
+func TestVersionActions(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddTenant("public", admin.TenantInfo{})
+	c.server.AddNamespace("public/default")
+	c.server.AddTopic("persistent://public/default/orders")
+	c.server.SetVersion("2.7.5")
+
+	c.run("namespaces", "grant-permission", "public/default", "--role", "app", "--actions", "produce,packages")
+	c.run("topics", "grant-permission", "orders", "--role", "app", "--actions", "packages")
+	c.run("namespaces", "grant-permission", "public/default", "--role", "app", "--actions", "produce")
+	c.run("namespaces", "permissions", "public/default")
+	c.check()
+}
+
+func TestVersionUnknown(t *testing.T) {
+	t.Parallel()
+	c := newCommandTest(t)
+	c.server.AddTenant("public", admin.TenantInfo{})
+	c.server.AddNamespace("public/default")
+
+	// The brokers older than the version endpoint are not checked
+	c.server.SetVersion("")
+	c.run("tenants", "list")
+	c.run("namespaces", "grant-permission", "public/default", "--role", "app", "--actions", "packages")
+	c.run("api", "GET", "/admin/v2/brokers/version")
+	c.check()
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, test := range []struct {
+		a, b     string
+		expected int
+	}{
+		{"2.10.0", "2.10.0", 0},
+		{"2.10.0", "2.9.3", 1},
+		{"2.9.3", "2.10.0", -1},
+		{"2.0", "2.0.0", 0},
+		{"2.11.0-SNAPSHOT", "2.11.0", 0},
+		{"1.22.1-incubating", "2.0.0", -1},
+		{"3.0.0.1", "3.0.0", 1},
+	} {
+		if actual := compareVersions(test.a, test.b); actual != test.expected {
+			t.Errorf("compareVersions(%s, %s) = %d, expected %d", test.a, test.b, actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package names
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NamespaceName is the name of a namespace, in the form <tenant>/<namespace>
+// or, for the legacy namespaces of Pulsar 1.x, <tenant>/<cluster>/<namespace>
+type NamespaceName struct {
+	tenant    string
+	cluster   string
+	localName string
+}
+
+// Characters allowed in the tenant, cluster and namespace names, like in
+// Pulsar's NamedEntity
+var namedEntityPattern = regexp.MustCompile(`^[-=:.\w]+$`)
+
+func NamespaceNameParse(namespace string) (*NamespaceName, error) {
+	parts := strings.Split(namespace, "/")
+
+	namespaceName := NamespaceName{}
+	if len(parts) == 2 {
+		namespaceName.tenant = parts[0]
+		namespaceName.localName = parts[1]
+	} else if len(parts) == 3 {
+		// Legacy namespace name that includes cluster name
+		namespaceName.tenant = parts[0]
+		namespaceName.cluster = parts[1]
+		namespaceName.localName = parts[2]
+	} else {
+		return nil, fmt.Errorf(
+			"invalid namespace name '%s', it should be in the format of <tenant>/<namespace>", namespace)
+	}
+
+	for _, part := range parts {
+		if err := validateName(part); err != nil {
+			return nil, fmt.Errorf("invalid namespace name '%s': %s", namespace, err)
+		}
+	}
+	return &namespaceName, nil
+}
+
+// Tenant returns the name of the tenant owning the namespace
+func (namespaceName *NamespaceName) Tenant() string {
+	return namespaceName.tenant
+}
+
+// Cluster returns the cluster of a legacy namespace, or "" for the others
+func (namespaceName *NamespaceName) Cluster() string {
+	return namespaceName.cluster
+}
+
+// Namespace returns the name of the namespace within the tenant
+func (namespaceName *NamespaceName) Namespace() string {
+	return namespaceName.localName
+}
+
+func (namespaceName *NamespaceName) isV2() bool {
+	return namespaceName.cluster == ""
+}
+
+// RestPath returns the namespace part of the admin API paths, eg:
+// my-tenant/my-namespace
+func (namespaceName *NamespaceName) RestPath() string {
+	if namespaceName.isV2() {
+		return fmt.Sprintf("%s/%s", namespaceName.tenant, namespaceName.localName)
+	} else {
+		return fmt.Sprintf("%s/%s/%s", namespaceName.tenant, namespaceName.cluster, namespaceName.localName)
+	}
+}
+
+// AdminPath returns the path of the namespace in the admin API: the v1 API
+// for the legacy namespace names that include a cluster and the v2 API for
+// the others
+func (namespaceName *NamespaceName) AdminPath() string {
+	if namespaceName.isV2() {
+		return "/admin/v2/namespaces/" + namespaceName.RestPath()
+	} else {
+		return "/admin/namespaces/" + namespaceName.RestPath()
+	}
+}
+
+func (namespaceName *NamespaceName) String() string {
+	return namespaceName.RestPath()
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("empty name")
+	}
+	if !namedEntityPattern.MatchString(name) {
+		return fmt.Errorf("'%s' has invalid characters, only letters, digits and -=:._ are allowed", name)
+	}
+	return nil
+}
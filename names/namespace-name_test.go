@@ -0,0 +1,36 @@
+package names
+
+import "testing"
+
+func TestNamespaceNameParse(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		tenant    string
+		cluster   string
+		restPath  string
+		adminPath string
+	}{
+		{"my-tenant/my-namespace", "my-tenant", "", "my-tenant/my-namespace",
+			"/admin/v2/namespaces/my-tenant/my-namespace"},
+		{"my-tenant/us-west/my-namespace", "my-tenant", "us-west", "my-tenant/us-west/my-namespace",
+			"/admin/namespaces/my-tenant/us-west/my-namespace"},
+		{"a_b/c=d:e.f", "a_b", "", "a_b/c=d:e.f", "/admin/v2/namespaces/a_b/c=d:e.f"},
+	} {
+		namespaceName, err := NamespaceNameParse(test.name)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if namespaceName.Tenant() != test.tenant || namespaceName.Cluster() != test.cluster ||
+			namespaceName.RestPath() != test.restPath || namespaceName.AdminPath() != test.adminPath ||
+			namespaceName.String() != test.name {
+			t.Errorf("%s: unexpected %#v", test.name, namespaceName)
+		}
+	}
+
+	for _, name := range []string{"", "my-namespace", "my-tenant/", "/my-namespace", "a/b/c/d", "my tenant/ns", "t/n$"} {
+		if _, err := NamespaceNameParse(name); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -1,20 +1,17 @@
-package util
+package names
 
 import (
-	"strings"
-	"log"
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 type TopicName struct {
 	completeTopicName string
 
-	domain           string
-	tenant           string
-	cluster          string
-	namespacePortion string
-	localName        string
+	domain        string
+	namespaceName *NamespaceName
+	localName     string
 }
 
 const Persistent = "persistent"
@@ -24,7 +21,7 @@ const PublicTenant = "public"
 const DefaultNamespace = "default"
 const PartitionedTopicSuffix = "-partition-"
 
-func TopicNameParse(completeTopicName string) *TopicName {
+func TopicNameParse(completeTopicName string) (*TopicName, error) {
 	topicName := TopicName{}
 
 	// The topic name can be in two different forms, one is fully qualified topic name,
@@ -39,8 +36,8 @@ func TopicNameParse(completeTopicName string) *TopicName {
 		} else if len(parts) == 1 {
 			completeTopicName = Persistent + "://" + PublicTenant + "/" + DefaultNamespace + "/" + parts[0]
 		} else {
-			log.Fatal(
-				"Invalid short topic name '" + completeTopicName + "', it should be in the format of <tenant>/<namespace>/<topic> or <topic>")
+			return nil, fmt.Errorf("invalid short topic name '%s', it should be in the format of "+
+				"<tenant>/<namespace>/<topic> or <topic>", completeTopicName)
 		}
 	}
 
@@ -53,7 +50,8 @@ func TopicNameParse(completeTopicName string) *TopicName {
 	parts := strings.SplitN(completeTopicName, "://", 2)
 	topicName.domain = parts[0]
 	if topicName.domain != Persistent && topicName.domain != NonPersistent {
-		log.Fatal("Invalid topic domain: ", topicName.domain)
+		return nil, fmt.Errorf("invalid topic domain '%s', it should be %s or %s",
+			topicName.domain, Persistent, NonPersistent)
 	}
 
 	rest := parts[1]
@@ -65,37 +63,69 @@ func TopicNameParse(completeTopicName string) *TopicName {
 	// 1. some/name/xyz//
 	// 2. /xyz-123/feeder-2
 	parts = strings.SplitN(rest, "/", 4)
+	var namespace string
 	if len(parts) == 3 {
 		// New topic name without cluster name
-		topicName.tenant = parts[0]
-		topicName.cluster = ""
-		topicName.namespacePortion = parts[1]
+		namespace = parts[0] + "/" + parts[1]
 		topicName.localName = parts[2]
 	} else if len(parts) == 4 {
 		// Legacy topic name that includes cluster name
-		topicName.tenant = parts[0]
-		topicName.cluster = parts[1]
-		topicName.namespacePortion = parts[2]
+		namespace = parts[0] + "/" + parts[1] + "/" + parts[2]
 		topicName.localName = parts[3]
 	} else {
-		log.Fatal("Invalid topic name: " + completeTopicName);
+		return nil, fmt.Errorf("invalid topic name '%s'", completeTopicName)
+	}
+
+	if topicName.localName == "" {
+		return nil, fmt.Errorf("invalid topic name '%s': empty local name", completeTopicName)
 	}
 
-	return &topicName
+	namespaceName, err := NamespaceNameParse(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("invalid topic name '%s': %s", completeTopicName, err)
+	}
+	topicName.namespaceName = namespaceName
+
+	return &topicName, nil
+}
+
+// Domain returns the domain of the topic: persistent or non-persistent
+func (topicName *TopicName) Domain() string {
+	return topicName.domain
+}
+
+// NamespaceName returns the name of the namespace of the topic
+func (topicName *TopicName) NamespaceName() *NamespaceName {
+	return topicName.namespaceName
+}
+
+// LocalName returns the name of the topic within its namespace
+func (topicName *TopicName) LocalName() string {
+	return topicName.localName
 }
 
 func (topicName *TopicName) isV2() bool {
-	return topicName.cluster == ""
+	return topicName.namespaceName.isV2()
 }
 
 func (topicName *TopicName) RestPath() string {
+	return fmt.Sprintf("%s/%s/%s", topicName.domain, topicName.namespaceName.RestPath(), topicName.encodedLocalName())
+}
+
+// AdminPath returns the path of the topic in the admin API: the v1 API for the
+// legacy topic names that include a cluster and the v2 API for the others
+func (topicName *TopicName) AdminPath() string {
 	if topicName.isV2() {
-		return fmt.Sprintf("%s/%s/%s/%s", topicName.domain, topicName.tenant, topicName.namespacePortion, topicName.encodedLocalName())
+		return "/admin/v2/" + topicName.RestPath()
 	} else {
-		return fmt.Sprintf("%s/%s/%s/%s/%s", topicName.domain, topicName.tenant, topicName.cluster, topicName.namespacePortion, topicName.encodedLocalName())
+		return "/admin/" + topicName.RestPath()
 	}
 }
 
+func (topicName *TopicName) String() string {
+	return topicName.completeTopicName
+}
+
 func (topicName *TopicName) encodedLocalName() string {
 	return url.PathEscape(topicName.localName)
 }
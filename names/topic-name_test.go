@@ -0,0 +1,31 @@
+package names
+
+import "testing"
+
+func TestTopicNameParse(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		adminPath string
+	}{
+		{"my-topic", "/admin/v2/persistent/public/default/my-topic"},
+		{"my-tenant/my-namespace/my-topic", "/admin/v2/persistent/my-tenant/my-namespace/my-topic"},
+		{"non-persistent://my-tenant/my-namespace/my-topic", "/admin/v2/non-persistent/my-tenant/my-namespace/my-topic"},
+		{"persistent://my-tenant/us-west/my-namespace/my-topic", "/admin/persistent/my-tenant/us-west/my-namespace/my-topic"},
+		{"persistent://my-tenant/us-west/my-namespace/a/b", "/admin/persistent/my-tenant/us-west/my-namespace/a%2Fb"},
+	} {
+		topicName, err := TopicNameParse(test.name)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if topicName.AdminPath() != test.adminPath {
+			t.Errorf("%s: got %s, expected %s", test.name, topicName.AdminPath(), test.adminPath)
+		}
+	}
+
+	for _, name := range []string{"a/b", "queue://t/n/topic", "persistent://t/topic", "persistent://t/n/", "persistent://t/n$/topic"} {
+		if _, err := TopicNameParse(name); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
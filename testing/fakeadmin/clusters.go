@@ -0,0 +1,197 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakeadmin
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+// AddCluster configures a cluster, replacing any existing one with the same
+// name
+func (s *Server) AddCluster(name string, cluster admin.ClusterData) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.clusters[name] = cluster
+}
+
+// AddFailureDomain configures a failure domain of an existing cluster
+func (s *Server) AddFailureDomain(cluster string, name string, domain admin.FailureDomain) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.failureDomains[cluster] == nil {
+		s.failureDomains[cluster] = map[string]admin.FailureDomain{}
+	}
+	s.failureDomains[cluster][name] = domain
+}
+
+// clusterNames returns the names of the clusters, sorted
+func (s *Server) clusterNames() []string {
+	names := make([]string, 0, len(s.clusters))
+	for name := range s.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Server) serveClusters(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 0:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, s.clusterNames())
+
+	case len(segments) == 1:
+		s.serveCluster(w, r, segments[0])
+
+	case len(segments) <= 3 && segments[1] == "failureDomains":
+		if _, ok := s.clusters[segments[0]]; !ok {
+			writeError(w, http.StatusNotFound, "Cluster does not exist")
+			return
+		}
+		if len(segments) == 2 {
+			s.serveFailureDomains(w, r, segments[0])
+		} else {
+			s.serveFailureDomain(w, r, segments[0], segments[2])
+		}
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) serveCluster(w http.ResponseWriter, r *http.Request, name string) {
+	cluster, exists := s.clusters[name]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeError(w, http.StatusNotFound, "Cluster does not exist")
+			return
+		}
+		writeJSON(w, http.StatusOK, cluster)
+
+	case http.MethodPut:
+		if exists {
+			writeError(w, http.StatusConflict, "Cluster already exists")
+			return
+		}
+		if !readJSON(w, r, &cluster) {
+			return
+		}
+		s.clusters[name] = cluster
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodPost:
+		if !exists {
+			writeError(w, http.StatusNotFound, "Cluster does not exist")
+			return
+		}
+		cluster = admin.ClusterData{}
+		if !readJSON(w, r, &cluster) {
+			return
+		}
+		s.clusters[name] = cluster
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !exists {
+			writeError(w, http.StatusNotFound, "Cluster does not exist")
+			return
+		}
+		for _, tenant := range s.tenants {
+			if contains(tenant.AllowedClusters, name) {
+				writeError(w, http.StatusPreconditionFailed, "Cluster not empty")
+				return
+			}
+		}
+		delete(s.clusters, name)
+		delete(s.failureDomains, name)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) serveFailureDomains(w http.ResponseWriter, r *http.Request, cluster string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+
+	domains := s.failureDomains[cluster]
+	if domains == nil {
+		domains = map[string]admin.FailureDomain{}
+	}
+	writeJSON(w, http.StatusOK, domains)
+}
+
+func (s *Server) serveFailureDomain(w http.ResponseWriter, r *http.Request, cluster string, name string) {
+	domain, exists := s.failureDomains[cluster][name]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeError(w, http.StatusNotFound, "Domain does not exist")
+			return
+		}
+		writeJSON(w, http.StatusOK, domain)
+
+	case http.MethodPost:
+		// Creates or replaces the domain. A broker can only belong to one
+		// domain of the cluster.
+		domain = admin.FailureDomain{}
+		if !readJSON(w, r, &domain) {
+			return
+		}
+		for other, otherDomain := range s.failureDomains[cluster] {
+			if other == name {
+				continue
+			}
+			for _, broker := range domain.Brokers {
+				if contains(otherDomain.Brokers, broker) {
+					writeError(w, http.StatusConflict,
+						fmt.Sprintf("Domain %s is already configured with broker %s", other, broker))
+					return
+				}
+			}
+		}
+		if s.failureDomains[cluster] == nil {
+			s.failureDomains[cluster] = map[string]admin.FailureDomain{}
+		}
+		s.failureDomains[cluster][name] = domain
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !exists {
+			writeError(w, http.StatusNotFound, "Domain does not exist")
+			return
+		}
+		delete(s.failureDomains[cluster], name)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w)
+	}
+}
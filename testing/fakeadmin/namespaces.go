@@ -0,0 +1,218 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakeadmin
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+// Number of bundles of the namespaces created without an explicit number,
+// like the default of the brokers
+const defaultNumBundles = 4
+
+// newPolicies returns the policies of a new namespace, with the defaults of
+// the brokers
+func newPolicies() *admin.Policies {
+	return &admin.Policies{
+		AuthPolicies: admin.AuthPolicies{
+			NamespaceAuth:         map[string][]string{},
+			DestinationAuth:       map[string]map[string][]string{},
+			SubscriptionAuthRoles: map[string][]string{},
+		},
+		ReplicationClusters:  []string{},
+		BacklogQuotaMap:      map[string]admin.BacklogQuota{},
+		Bundles:              newBundles(defaultNumBundles),
+		SubscriptionAuthMode: "None",
+		OffloadThreshold:     -1,
+	}
+}
+
+// newBundles splits the hash range in equal bundles
+func newBundles(numBundles int) *admin.BundlesData {
+	bundles := &admin.BundlesData{NumBundles: numBundles}
+	step := uint64(0x100000000) / uint64(numBundles)
+	for i := 0; i < numBundles; i++ {
+		bundles.Boundaries = append(bundles.Boundaries, fmt.Sprintf("0x%08x", uint64(i)*step))
+	}
+	bundles.Boundaries = append(bundles.Boundaries, "0xffffffff")
+	return bundles
+}
+
+// AddNamespace creates a namespace, in the form <tenant>/<namespace> or
+// <tenant>/<cluster>/<namespace>, with the default policies. The tenant is
+// not validated.
+func (s *Server) AddNamespace(namespace string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.namespaces[namespace] = newPolicies()
+}
+
+// Namespace returns a copy of the policies of a namespace, or nil if it does
+// not exist
+func (s *Server) Namespace(namespace string) *admin.Policies {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	policies, ok := s.namespaces[namespace]
+	if !ok {
+		return nil
+	}
+	result := *policies
+	result.ReplicationClusters = append([]string{}, policies.ReplicationClusters...)
+	return &result
+}
+
+// serveNamespaces handles the namespace paths, where the names have
+// nameSegments segments: 2 in the v2 API and 3, with the cluster, in the v1 one
+func (s *Server) serveNamespaces(w http.ResponseWriter, r *http.Request, segments []string, nameSegments int) {
+	if len(segments) == 0 {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	if _, ok := s.tenants[segments[0]]; !ok {
+		writeError(w, http.StatusNotFound, "Tenant does not exist")
+		return
+	}
+
+	switch {
+	case len(segments) == 1:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+
+		names := []string{}
+		for namespace := range s.namespaces {
+			if strings.HasPrefix(namespace, segments[0]+"/") {
+				names = append(names, namespace)
+			}
+		}
+		sort.Strings(names)
+		writeJSON(w, http.StatusOK, names)
+
+	case len(segments) == nameSegments:
+		s.serveNamespace(w, r, segments[0], strings.Join(segments, "/"), nameSegments)
+
+	case len(segments) > nameSegments:
+		namespace := strings.Join(segments[:nameSegments], "/")
+		policies, ok := s.namespaces[namespace]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Namespace does not exist")
+			return
+		}
+
+		if len(segments) == nameSegments+1 && segments[nameSegments] == topicsSegment(nameSegments) {
+			if r.Method != http.MethodGet {
+				methodNotAllowed(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, s.namespaceTopics("persistent", namespace))
+			return
+		}
+		s.servePolicy(w, r, segments[0], policies, segments[nameSegments:])
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// topicsSegment returns the last segment of the path listing the topics of a
+// namespace, which are still called destinations in the v1 API
+func topicsSegment(nameSegments int) string {
+	if nameSegments == 3 {
+		return "destinations"
+	}
+	return "topics"
+}
+
+func (s *Server) serveNamespace(w http.ResponseWriter, r *http.Request, tenant string, namespace string, nameSegments int) {
+	policies, exists := s.namespaces[namespace]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeError(w, http.StatusNotFound, "Namespace does not exist")
+			return
+		}
+		writeJSON(w, http.StatusOK, policies)
+
+	case http.MethodPut:
+		if exists {
+			writeError(w, http.StatusConflict, "Namespace already exists")
+			return
+		}
+
+		// The v2 API takes the initial policies and the v1 API only the
+		// bundles
+		requested := &admin.Policies{}
+		if nameSegments == 3 {
+			requested.Bundles = &admin.BundlesData{}
+			if !readJSON(w, r, requested.Bundles) {
+				return
+			}
+		} else if !readJSON(w, r, requested) {
+			return
+		}
+
+		policies = newPolicies()
+		if requested.Bundles != nil && requested.Bundles.NumBundles > 0 {
+			policies.Bundles = newBundles(requested.Bundles.NumBundles)
+		}
+		if requested.ReplicationClusters != nil {
+			policies.ReplicationClusters = requested.ReplicationClusters
+		}
+		if !s.validateReplicationClusters(w, tenant, policies.ReplicationClusters) {
+			return
+		}
+		s.namespaces[namespace] = policies
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !exists {
+			writeError(w, http.StatusNotFound, "Namespace does not exist")
+			return
+		}
+		if len(s.namespaceTopics("persistent", namespace)) > 0 ||
+			len(s.namespaceTopics("non-persistent", namespace)) > 0 {
+			writeError(w, http.StatusConflict, "Cannot delete non empty namespace")
+			return
+		}
+		delete(s.namespaces, namespace)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// validateReplicationClusters checks that the tenant is allowed to use the
+// clusters
+func (s *Server) validateReplicationClusters(w http.ResponseWriter, tenant string, clusters []string) bool {
+	for _, cluster := range clusters {
+		if !contains(s.tenants[tenant].AllowedClusters, cluster) {
+			writeError(w, http.StatusForbidden, fmt.Sprintf(
+				"Cluster [%s] is not in the list of allowed clusters list for tenant [%s]", cluster, tenant))
+			return false
+		}
+	}
+	return true
+}
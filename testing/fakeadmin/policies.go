@@ -0,0 +1,144 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakeadmin
+
+import (
+	"net/http"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+// servePolicy handles the paths of the individual policies of a namespace,
+// eg: /admin/v2/namespaces/my-tenant/my-namespace/retention
+func (s *Server) servePolicy(w http.ResponseWriter, r *http.Request, tenant string, policies *admin.Policies,
+	segments []string) {
+	if segments[0] == "permissions" && len(segments) <= 2 {
+		serveNamespacePermissions(w, r, policies, segments[1:])
+		return
+	}
+	if len(segments) != 1 {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	switch policy := segments[0]; {
+	case policy == "retention" && r.Method == http.MethodGet:
+		// Like the brokers, answer without body when it's not set
+		if policies.RetentionPolicies == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, http.StatusOK, policies.RetentionPolicies)
+
+	case policy == "retention" && r.Method == http.MethodPost:
+		retention := &admin.RetentionPolicies{}
+		if !readJSON(w, r, retention) {
+			return
+		}
+		if !quotaWithinRetention(policies.BacklogQuotaMap[admin.DestinationStorage], retention) {
+			writeError(w, http.StatusPreconditionFailed,
+				"Retention Quota must exceed configured backlog quota for namespace.")
+			return
+		}
+		policies.RetentionPolicies = retention
+		w.WriteHeader(http.StatusNoContent)
+
+	case policy == "replication" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, policies.ReplicationClusters)
+
+	case policy == "replication" && r.Method == http.MethodPost:
+		clusters := []string{}
+		if !readJSON(w, r, &clusters) {
+			return
+		}
+		if contains(clusters, "global") {
+			writeError(w, http.StatusPreconditionFailed, "Cannot specify global in the list of replication clusters")
+			return
+		}
+		if !s.validateReplicationClusters(w, tenant, clusters) {
+			return
+		}
+		policies.ReplicationClusters = clusters
+		w.WriteHeader(http.StatusNoContent)
+
+	case policy == "backlogQuotaMap" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, policies.BacklogQuotaMap)
+
+	case policy == "backlogQuota" && (r.Method == http.MethodPost || r.Method == http.MethodDelete):
+		quotaType := r.URL.Query().Get("backlogQuotaType")
+		if quotaType == "" {
+			quotaType = admin.DestinationStorage
+		}
+
+		if r.Method == http.MethodDelete {
+			delete(policies.BacklogQuotaMap, quotaType)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		quota := admin.BacklogQuota{}
+		if !readJSON(w, r, &quota) {
+			return
+		}
+		if !quotaWithinRetention(quota, policies.RetentionPolicies) {
+			writeError(w, http.StatusPreconditionFailed,
+				"Backlog Quota exceeds configured retention quota for namespace. Please increase retention quota and retry")
+			return
+		}
+		policies.BacklogQuotaMap[quotaType] = quota
+		w.WriteHeader(http.StatusNoContent)
+
+	case policy == "retention" || policy == "replication" || policy == "backlogQuotaMap" || policy == "backlogQuota":
+		methodNotAllowed(w)
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// quotaWithinRetention checks, like the brokers, that the backlog quota is
+// lower than the retention size, when both are limited
+func quotaWithinRetention(quota admin.BacklogQuota, retention *admin.RetentionPolicies) bool {
+	if retention == nil || retention.RetentionSizeInMB <= 0 || quota.Limit <= 0 {
+		return true
+	}
+	return quota.Limit < retention.RetentionSizeInMB*1024*1024
+}
+
+// serveNamespacePermissions lists the permissions of a namespace, or grants
+// or revokes the ones of the role in segments
+func serveNamespacePermissions(w http.ResponseWriter, r *http.Request, policies *admin.Policies, segments []string) {
+	permissions := policies.AuthPolicies.NamespaceAuth
+
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, permissions)
+
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		actions := []string{}
+		if !readJSON(w, r, &actions) {
+			return
+		}
+		permissions[segments[0]] = actions
+		w.WriteHeader(http.StatusNoContent)
+
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		delete(permissions, segments[0])
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakeadmin provides an in-memory fake of the Pulsar admin REST API,
+// to test the admin client and the commands without a running cluster.
+//
+// It implements the /admin/v2 endpoints for the broker version, clusters,
+// failure domains, tenants, namespaces and topics, and the /admin (v1) ones
+// for the legacy namespaces that include a cluster, eg:
+// my-tenant/us-west/my-namespace. It answers with the same status codes and
+// reason bodies as the Pulsar brokers, eg:
+//
+//	server := fakeadmin.NewServer()
+//	defer server.Close()
+//	server.AddCluster("us-west", admin.ClusterData{ServiceUrl: "http://us-west:8080"})
+//
+//	client, err := admin.New(&admin.Config{WebServiceUrl: server.URL})
+package fakeadmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+// DefaultVersion is the version of Pulsar reported by a new Server
+const DefaultVersion = "2.10.0"
+
+// Server is a fake Pulsar admin service, listening on a local address. It is
+// safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	lock           sync.Mutex
+	blocking       bool
+	version        string
+	clusters       map[string]admin.ClusterData
+	failureDomains map[string]map[string]admin.FailureDomain
+	tenants        map[string]admin.TenantInfo
+	namespaces     map[string]*admin.Policies
+	topics         map[string]bool
+}
+
+// NewServer starts a fake admin service without any resource. It must be
+// closed when done.
+func NewServer() *Server {
+	s := &Server{
+		version:        DefaultVersion,
+		clusters:       map[string]admin.ClusterData{},
+		failureDomains: map[string]map[string]admin.FailureDomain{},
+		tenants:        map[string]admin.TenantInfo{},
+		namespaces:     map[string]*admin.Policies{},
+		topics:         map[string]bool{},
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// SetVersion changes the version of Pulsar reported by the server, eg: to
+// test the commands with older brokers. With an empty version, the server
+// behaves like the brokers that predate the version endpoint.
+func (s *Server) SetVersion(version string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.version = version
+}
+
+// SetBlocking makes the server hold the requests without answering until the
+// clients give up, eg: to test the timeouts and the cancellation of the
+// commands
+func (s *Server) SetBlocking(blocking bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.blocking = blocking
+}
+
+// ServeHTTP dispatches the requests to the handler of each resource
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	if s.blocking {
+		s.lock.Unlock()
+		<-r.Context().Done()
+		return
+	}
+	defer s.lock.Unlock()
+
+	// The namespaces of the v1 API have one more segment, the cluster
+	namespaceSegments := 2
+	path := strings.TrimPrefix(r.URL.Path, "/admin/v2/")
+	if path == r.URL.Path {
+		namespaceSegments = 3
+		path = strings.TrimPrefix(r.URL.Path, "/admin/")
+	}
+	if path == r.URL.Path {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	switch segments[0] {
+	case "brokers":
+		s.serveBrokers(w, r, segments[1:])
+	case "clusters":
+		s.serveClusters(w, r, segments[1:])
+	case "tenants":
+		s.serveTenants(w, r, segments[1:])
+	case "namespaces":
+		s.serveNamespaces(w, r, segments[1:], namespaceSegments)
+	case "persistent", "non-persistent":
+		s.serveTopics(w, r, segments[0], segments[1:], namespaceSegments)
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) serveBrokers(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 1 || segments[0] != "version" || s.version == "" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+
+	// Like the brokers, send the version as plain text
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(s.version))
+}
+
+// writeError sends an error response, with the reason in the same JSON body
+// as the brokers
+func writeError(w http.ResponseWriter, status int, reason string) {
+	writeJSON(w, status, admin.ErrorReason{Reason: reason})
+}
+
+func writeJSON(w http.ResponseWriter, status int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(obj)
+}
+
+// readJSON decodes the request body into obj. An empty body leaves obj
+// unchanged.
+func readJSON(w http.ResponseWriter, r *http.Request, obj interface{}) bool {
+	if r.ContentLength == 0 {
+		return true
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakeadmin
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+// AddTenant configures a tenant, replacing any existing one with the same
+// name. The allowed clusters are not validated.
+func (s *Server) AddTenant(name string, tenant admin.TenantInfo) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.tenants[name] = tenant
+}
+
+func (s *Server) serveTenants(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch len(segments) {
+	case 0:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+
+		names := make([]string, 0, len(s.tenants))
+		for name := range s.tenants {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		writeJSON(w, http.StatusOK, names)
+
+	case 1:
+		s.serveTenant(w, r, segments[0])
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) serveTenant(w http.ResponseWriter, r *http.Request, name string) {
+	tenant, exists := s.tenants[name]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeError(w, http.StatusNotFound, "Tenant does not exist")
+			return
+		}
+		writeJSON(w, http.StatusOK, tenant)
+
+	case http.MethodPut:
+		if exists {
+			writeError(w, http.StatusConflict, "Tenant already exists")
+			return
+		}
+		if !readJSON(w, r, &tenant) || !s.validateClusters(w, tenant.AllowedClusters) {
+			return
+		}
+		s.tenants[name] = tenant
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodPost:
+		if !exists {
+			writeError(w, http.StatusNotFound, "Tenant does not exist")
+			return
+		}
+		tenant = admin.TenantInfo{}
+		if !readJSON(w, r, &tenant) || !s.validateClusters(w, tenant.AllowedClusters) {
+			return
+		}
+		s.tenants[name] = tenant
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !exists {
+			writeError(w, http.StatusNotFound, "Tenant does not exist")
+			return
+		}
+		for namespace := range s.namespaces {
+			if strings.HasPrefix(namespace, name+"/") {
+				writeError(w, http.StatusConflict, "The tenant still has active namespaces")
+				return
+			}
+		}
+		delete(s.tenants, name)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// validateClusters checks that all the clusters exist
+func (s *Server) validateClusters(w http.ResponseWriter, clusters []string) bool {
+	for _, cluster := range clusters {
+		if _, ok := s.clusters[cluster]; !ok {
+			writeError(w, http.StatusPreconditionFailed, "Clusters do not exist")
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+// Copyright © 2018 NAME HERE <EMAIL ADDRESS>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakeadmin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/merlimat/pulsar-ctl/admin"
+)
+
+// AddTopic creates a non-partitioned topic, given with its full name, eg:
+// persistent://my-tenant/my-namespace/my-topic or
+// persistent://my-tenant/us-west/my-namespace/my-topic. The namespace is not
+// validated.
+func (s *Server) AddTopic(topic string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.topics[topic] = true
+}
+
+// namespaceTopics returns the full names of the topics of the given domain
+// in the namespace, sorted
+func (s *Server) namespaceTopics(domain string, namespace string) []string {
+	prefix := domain + "://" + namespace + "/"
+
+	topics := []string{}
+	for _, topic := range sortedKeys(s.topics) {
+		if strings.HasPrefix(topic, prefix) {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+func (s *Server) serveTopics(w http.ResponseWriter, r *http.Request, domain string, segments []string, nameSegments int) {
+	// The paths are <namespace>, <namespace>/<topic> and
+	// <namespace>/<topic>/permissions[/<role>]
+	permissions := len(segments) > nameSegments+1 && segments[nameSegments+1] == "permissions"
+	if len(segments) < nameSegments || (len(segments) > nameSegments+1 && !permissions) ||
+		len(segments) > nameSegments+3 {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	namespace := strings.Join(segments[:nameSegments], "/")
+	policies, ok := s.namespaces[namespace]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Namespace does not exist")
+		return
+	}
+
+	if len(segments) == nameSegments {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, s.namespaceTopics(domain, namespace))
+		return
+	}
+
+	topic := domain + "://" + namespace + "/" + segments[nameSegments]
+	if permissions {
+		serveTopicPermissions(w, r, policies, topic, segments[nameSegments+2:])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if s.topics[topic] {
+			writeError(w, http.StatusConflict, "This topic already exists")
+			return
+		}
+		s.topics[topic] = true
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !s.topics[topic] {
+			writeError(w, http.StatusNotFound, "Topic not found")
+			return
+		}
+		delete(s.topics, topic)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// serveTopicPermissions lists the permissions of a topic, including the ones
+// of its namespace like the brokers, or grants or revokes the ones of the
+// role in segments
+func serveTopicPermissions(w http.ResponseWriter, r *http.Request, policies *admin.Policies, topic string,
+	segments []string) {
+	permissions := policies.AuthPolicies.DestinationAuth[topic]
+
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		merged := map[string][]string{}
+		for role, actions := range policies.AuthPolicies.NamespaceAuth {
+			merged[role] = actions
+		}
+		for role, actions := range permissions {
+			merged[role] = actions
+		}
+		writeJSON(w, http.StatusOK, merged)
+
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		actions := []string{}
+		if !readJSON(w, r, &actions) {
+			return
+		}
+		if permissions == nil {
+			permissions = map[string][]string{}
+			policies.AuthPolicies.DestinationAuth[topic] = permissions
+		}
+		permissions[segments[0]] = actions
+		w.WriteHeader(http.StatusNoContent)
+
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		if _, ok := permissions[segments[0]]; !ok {
+			writeError(w, http.StatusPreconditionFailed, "Permissions are not set at the topic level")
+			return
+		}
+		delete(permissions, segments[0])
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w)
+	}
+}